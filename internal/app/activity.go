@@ -20,9 +20,9 @@ func runActivity(ctx context.Context, state *state, args []string) int {
 		printActivityUsage(state.Out)
 		return 0
 	default:
-		writeLine(state.Err, "error: unknown activity command:", args[0])
+		code := reportError(state, usageErrorf("unknown activity command: %s", args[0]))
 		printActivityUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -45,6 +45,7 @@ func runActivityList(ctx context.Context, state *state, args []string) int {
 	var annotateNotes bool
 	var annotateParents bool
 	var all bool
+	var view listView
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.IntVar(&limit, "limit", 30, "Max events per page (1-100)")
@@ -62,27 +63,27 @@ func runActivityList(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&annotateNotes, "annotate-notes", false, "Include note info in extra_data")
 	fs.BoolVar(&annotateParents, "annotate-parents", false, "Include parent info in extra_data")
 	fs.BoolVar(&all, "all", false, "Fetch all pages")
+	view.register(fs)
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printActivityUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) > 0 {
-		writeLine(state.Err, "error: unexpected arguments")
-		return 2
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if _, _, err := view.parse(activityView); err != nil {
+		return reportError(state, usageError{err})
 	}
 	if initiatorID != "" && initiatorIDNull {
-		writeLine(state.Err, "error: cannot use --initiator-id and --initiator-id-null together")
-		return 2
+		return reportError(state, usageErrorf("cannot use --initiator-id and --initiator-id-null together"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	params := map[string]string{}
@@ -135,32 +136,38 @@ func runActivityList(ctx context.Context, state *state, args []string) int {
 	if all {
 		activities, err := client.ListActivitiesAll(ctx, params)
 		if err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
-		if err := printActivities(state.Out, activities, state.Mode); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+		if view.active() {
+			err = view.print(ctx, state, client, activityView, activities, nil)
+		} else {
+			err = printActivities(state.Out, activities, state.Mode)
+		}
+		if err != nil {
+			return reportError(state, err)
 		}
 		return 0
 	}
 
 	activities, next, err := client.ListActivities(ctx, params)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+	if view.active() {
+		if err := view.print(ctx, state, client, activityView, activities, &next); err != nil {
+			return reportError(state, err)
+		}
+		return 0
 	}
 	if state.Mode == modeJSON {
 		payload := map[string]any{"results": activities, "next_cursor": next}
 		if err := printJSON(state.Out, payload); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printActivities(state.Out, activities, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
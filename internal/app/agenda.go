@@ -0,0 +1,275 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+const (
+	agendaDateLayout     = "2006-01-02"
+	agendaFloatingLayout = "2006-01-02T15:04:05"
+)
+
+// agendaDay is one day of an agenda view.
+type agendaDay struct {
+	Date    string      `json:"date"`
+	Overdue bool        `json:"overdue"`
+	Tasks   []todi.Task `json:"tasks"`
+}
+
+// agendaItem is a task with its due time resolved in the user's timezone.
+type agendaItem struct {
+	Task    todi.Task
+	Due     time.Time
+	Day     time.Time
+	Overdue bool
+}
+
+func runToday(ctx context.Context, state *state, args []string) int {
+	return runAgenda(ctx, state, "today", args)
+}
+
+func runUpcoming(ctx context.Context, state *state, args []string) int {
+	return runAgenda(ctx, state, "upcoming", args)
+}
+
+func runOverdue(ctx context.Context, state *state, args []string) int {
+	return runAgenda(ctx, state, "overdue", args)
+}
+
+// runAgenda lists due tasks grouped by day. today and upcoming include
+// overdue tasks; overdue lists only those. A task is overdue when its due
+// day is before today in the user's timezone, so one timed earlier today
+// stays under today.
+func runAgenda(ctx context.Context, state *state, view string, args []string) int {
+	fs := flag.NewFlagSet("todi "+view, flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var days int
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	if view == "upcoming" {
+		fs.IntVar(&days, "days", 7, "Number of days to show, starting today")
+	}
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printAgendaUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if view == "upcoming" && days < 1 {
+		return reportError(state, usageErrorf("--days must be at least 1"))
+	}
+
+	tasks, loc, err := agendaTasks(ctx, state)
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	today := startOfDay(time.Now().In(loc))
+	var end time.Time
+	switch view {
+	case "today":
+		end = today.AddDate(0, 0, 1)
+	case "upcoming":
+		end = today.AddDate(0, 0, days)
+	default:
+		end = today
+	}
+
+	var items []agendaItem
+	for _, task := range tasks {
+		due, ok := dueTime(task.Due, loc)
+		if !ok {
+			continue
+		}
+		item := agendaItem{Task: task, Due: due, Day: startOfDay(due)}
+		item.Overdue = item.Day.Before(today)
+		if view == "overdue" && !item.Overdue {
+			continue
+		}
+		if !item.Overdue && !item.Day.Before(end) {
+			continue
+		}
+		items = append(items, item)
+	}
+
+	if err := printAgenda(state.Out, groupAgenda(items), state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+// agendaTasks loads every active task and the user's timezone. When the
+// network is unavailable it retries against the cache, if one exists.
+func agendaTasks(ctx context.Context, state *state) ([]todi.Task, *time.Location, error) {
+	client, err := state.client()
+	if err != nil {
+		return nil, nil, err
+	}
+	tasks, loc, err := loadAgenda(ctx, client)
+	if err == nil || state.Offline || !isNetworkError(err) {
+		return tasks, loc, err
+	}
+
+	offline := *state
+	offline.Offline = true
+	offline.RefreshCache = false
+	client, cerr := offline.client()
+	if cerr != nil {
+		return nil, nil, err
+	}
+	tasks, loc, cerr = loadAgenda(ctx, client)
+	if cerr != nil {
+		return nil, nil, err
+	}
+	writeLine(state.Err, "warning: network unavailable, showing cached tasks")
+	return tasks, loc, nil
+}
+
+func loadAgenda(ctx context.Context, client *todi.Client) ([]todi.Task, *time.Location, error) {
+	tasks, err := client.ListTasksAll(ctx, map[string]string{})
+	if err != nil {
+		return nil, nil, err
+	}
+	loc := time.Local
+	user, err := client.CachedUserInfo(ctx)
+	switch {
+	case err == nil:
+		loc = user.Location()
+	case !client.Offline:
+		return nil, nil, err
+	}
+	return tasks, loc, nil
+}
+
+// isNetworkError reports whether err came from the transport: a network or
+// DNS failure, or a request refused because the client is offline.
+func isNetworkError(err error) bool {
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+	var netErr net.Error
+	var dnsErr *net.DNSError
+	return errors.As(err, &netErr) || errors.As(err, &dnsErr) || errors.Is(err, todi.ErrOffline)
+}
+
+// dueTime resolves a due date in loc. Floating datetimes are read as wall
+// time in loc, and all-day tasks are due at the start of their day.
+func dueTime(due *todi.Due, loc *time.Location) (time.Time, bool) {
+	if due == nil {
+		return time.Time{}, false
+	}
+	if due.Datetime != "" {
+		if t, err := time.Parse(time.RFC3339, due.Datetime); err == nil {
+			return t.In(loc), true
+		}
+		if t, err := time.ParseInLocation(agendaFloatingLayout, due.Datetime, loc); err == nil {
+			return t, true
+		}
+	}
+	if len(due.Date) > len(agendaDateLayout) {
+		if t, err := time.ParseInLocation(agendaFloatingLayout, due.Date, loc); err == nil {
+			return t, true
+		}
+	}
+	if t, err := time.ParseInLocation(agendaDateLayout, due.Date, loc); err == nil {
+		return t, true
+	}
+	return time.Time{}, false
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// groupAgenda buckets items by day, sorting each day by priority and then
+// due time.
+func groupAgenda(items []agendaItem) []agendaDay {
+	sort.SliceStable(items, func(i, j int) bool {
+		a, b := items[i], items[j]
+		if !a.Day.Equal(b.Day) {
+			return a.Day.Before(b.Day)
+		}
+		if a.Task.Priority != b.Task.Priority {
+			return a.Task.Priority > b.Task.Priority
+		}
+		return a.Due.Before(b.Due)
+	})
+	days := []agendaDay{}
+	for _, item := range items {
+		date := item.Day.Format(agendaDateLayout)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, agendaDay{Date: date, Overdue: item.Overdue})
+		}
+		day := &days[len(days)-1]
+		day.Tasks = append(day.Tasks, item.Task)
+	}
+	return days
+}
+
+func agendaHeading(day agendaDay) string {
+	heading := day.Date
+	if t, err := time.Parse(agendaDateLayout, day.Date); err == nil {
+		heading += " " + t.Format("Mon")
+	}
+	if day.Overdue {
+		heading += " (overdue)"
+	}
+	return heading
+}
+
+func printAgenda(out io.Writer, days []agendaDay, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		for i := range days {
+			days[i].Tasks = withTaskURLs(days[i].Tasks)
+		}
+		return printJSON(out, map[string]any{"results": days})
+	case modePlain:
+		for _, day := range days {
+			for _, task := range day.Tasks {
+				fields := append(taskPlainFields(task), day.Date, strconv.FormatBool(day.Overdue))
+				if _, err := fmt.Fprintln(out, strings.Join(fields, "\t")); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	default:
+		st := styleOf(out)
+		for i, day := range days {
+			if i > 0 {
+				if _, err := fmt.Fprintln(out); err != nil {
+					return err
+				}
+			}
+			heading := st.heading(agendaHeading(day))
+			if day.Overdue {
+				heading = st.paint(ansiRed, agendaHeading(day))
+			}
+			if _, err := fmt.Fprintln(out, heading); err != nil {
+				return err
+			}
+			if err := printTasks(out, day.Tasks, mode); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
@@ -2,14 +2,18 @@ package app
 
 import (
 	"context"
-	"errors"
 	"flag"
 	"fmt"
 	"io"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"time"
 
+	"github.com/mattjefferson/todi/internal/cache"
 	"github.com/mattjefferson/todi/internal/config"
+	"github.com/mattjefferson/todi/internal/journal"
 	"github.com/mattjefferson/todi/internal/todi"
 )
 
@@ -39,53 +43,112 @@ func Run(args []string) int {
 		return 0
 	}
 
+	mode, rendered, modeErr := parseOutputMode(globals.JSON, globals.Plain, globals.Output)
+	state := &state{
+		Out:      out,
+		Err:      errOut,
+		Mode:     mode,
+		NoInput:  globals.NoInput,
+		Quiet:    globals.Quiet,
+		Verbose:  globals.Verbose,
+		Rendered: rendered != "",
+	}
+	if modeErr != nil {
+		return reportError(state, usageError{modeErr})
+	}
+
 	configPath := globals.ConfigPath
 	if configPath == "" {
 		path, err := config.DefaultPath()
 		if err != nil {
-			if _, writeErr := fmt.Fprintln(errOut, "error:", err); writeErr != nil {
-				return 1
-			}
-			return 1
+			return reportError(state, err)
 		}
 		configPath = path
 	}
 
 	cfg, err := config.Load(configPath)
 	if err != nil {
-		if _, writeErr := fmt.Fprintln(errOut, "error:", err); writeErr != nil {
-			return 1
-		}
-		return 1
+		return reportError(state, err)
 	}
 
-	if globals.APIBase != "" {
-		cfg.APIBase = globals.APIBase
+	profile := firstNonEmpty(globals.Profile, os.Getenv("TODI_PROFILE"), cfg.Current)
+	account := cfg.Account(profile)
+	sources := configSources(globals, cfg, account)
+	apiBase := globals.APIBase
+	if apiBase == "" && account != nil {
+		apiBase = account.APIBase
 	}
-	if cfg.APIBase == "" {
-		cfg.APIBase = envOrDefault("TODOIST_API_BASE", defaultAPIBase)
+	if apiBase == "" {
+		apiBase = envOrDefault("TODOIST_API_BASE", defaultAPIBase)
 	}
 
-	mode, err := parseOutputMode(globals.JSON, globals.Plain)
+	retry, err := retryPolicy(globals, cfg)
 	if err != nil {
-		if _, writeErr := fmt.Fprintln(errOut, "error:", err); writeErr != nil {
-			return 2
+		return reportError(state, usageError{err})
+	}
+
+	cacheTTL, err := cacheTTL(cfg)
+	if err != nil {
+		return reportError(state, usageError{err})
+	}
+
+	match, err := matchModes(cfg)
+	if err != nil {
+		return reportError(state, usageError{err})
+	}
+
+	color, err := useColor(globals.NoColor, cfg, out)
+	if err != nil {
+		return reportError(state, usageError{err})
+	}
+
+	state.Config = cfg
+	state.ConfigPath = configPath
+	state.Sources = sources
+	state.Profile = profile
+	state.Account = account
+	state.APIBase = apiBase
+	state.LabelCLI = globals.LabelCLI || cfg.LabelCLI
+	state.Retry = retry
+	state.NoCache = globals.NoCache
+	state.RefreshCache = globals.Refresh
+	state.CacheTTL = cacheTTL
+	state.Match = match
+	state.Offline = globals.Offline
+
+	var render renderer
+	if globals.Format != "" {
+		if globals.JSON || globals.Plain || globals.Output != "" {
+			return reportError(state, usageErrorf("cannot combine --format with --json, --plain or --output"))
+		}
+		tmpl, err := parseFormat(globals.Format, cfg)
+		if err != nil {
+			return reportError(state, usageError{err})
+		}
+		render = templateRenderer{tmpl: tmpl}
+	} else if rendered != "" {
+		render = newRenderer(rendered)
+	}
+	if render == nil {
+		if color && (state.Mode == modeHuman || state.Mode == modeWide) {
+			state.Out = &colorOutput{Writer: out, labels: state.labelColors(ctx), location: state.userLocation(ctx)}
 		}
-		return 2
+		return dispatch(ctx, state, rest)
 	}
 
-	state := &state{
-		Out:        out,
-		Err:        errOut,
-		Mode:       mode,
-		NoInput:    globals.NoInput,
-		Quiet:      globals.Quiet,
-		Verbose:    globals.Verbose,
-		Config:     cfg,
-		ConfigPath: configPath,
-		LabelCLI:   globals.LabelCLI || cfg.LabelCLI,
+	output := &jsonOutput{out: out, render: render}
+	state.Out = output
+	state.Mode = modeJSON
+	state.Rendered = true
+	state.Stream = rendered != outputYAML
+	code = dispatch(ctx, state, rest)
+	if err := output.Flush(); err != nil {
+		return reportError(state, err)
 	}
+	return code
+}
 
+func dispatch(ctx context.Context, state *state, rest []string) int {
 	switch rest[0] {
 	case "task":
 		return runTask(ctx, state, rest[1:])
@@ -105,20 +168,36 @@ func Run(args []string) int {
 		return runUser(ctx, state, rest[1:])
 	case "auth":
 		return runAuth(ctx, state, rest[1:])
+	case "profile":
+		return runProfile(ctx, state, rest[1:])
 	case "config":
 		return runConfig(ctx, state, rest[1:])
+	case "cache":
+		return runCache(ctx, state, rest[1:])
+	case "sync":
+		return runSync(ctx, state, rest[1:])
+	case "completed":
+		return runCompleted(ctx, state, rest[1:])
+	case "reminder":
+		return runReminder(ctx, state, rest[1:])
+	case "filter":
+		return runFilter(ctx, state, rest[1:])
+	case "today":
+		return runToday(ctx, state, rest[1:])
+	case "upcoming":
+		return runUpcoming(ctx, state, rest[1:])
+	case "overdue":
+		return runOverdue(ctx, state, rest[1:])
 	case "help", "-h", "--help":
-		printUsage(out)
+		printUsage(state.Out)
 		return 0
 	default:
 		if isTaskSubcommand(rest[0]) {
 			return runTask(ctx, state, rest)
 		}
-		if _, writeErr := fmt.Fprintln(errOut, "error: unknown command:", rest[0]); writeErr != nil {
-			return 2
-		}
-		printUsage(errOut)
-		return 2
+		code := reportError(state, usageErrorf("unknown command: %s", rest[0]))
+		printUsage(state.Err)
+		return code
 	}
 }
 
@@ -132,8 +211,16 @@ type globalFlags struct {
 	NoInput     bool
 	NoColor     bool
 	ConfigPath  string
+	Profile     string
 	APIBase     string
 	LabelCLI    bool
+	Retries     int
+	RetryWait   string
+	NoCache     bool
+	Refresh     bool
+	Offline     bool
+	Format      string
+	Output      string
 }
 
 type state struct {
@@ -145,18 +232,146 @@ type state struct {
 	Verbose    bool
 	Config     *config.Config
 	ConfigPath string
-	LabelCLI   bool
+	// Profile is the selected profile; "" means the top-level account.
+	Profile string
+	// Account holds the selected profile's settings. It is nil when the
+	// profile does not exist yet.
+	Account *config.Profile
+	// APIBase is the resolved API base for the account.
+	APIBase string
+	// Sources names the flag or env var behind settings not taken from the
+	// user config; see configSources.
+	Sources      map[string]string
+	LabelCLI     bool
+	Retry        todi.RetryPolicy
+	NoCache      bool
+	RefreshCache bool
+	CacheTTL     time.Duration
+	Offline      bool
+	// Match holds the name lookup modes per resource (match.<resource>).
+	Match map[string][]todi.MatchMode
+	// Rendered is set when the JSON output is re-rendered by --format or
+	// --output csv|tsv|ndjson|yaml; errors stay human-readable.
+	Rendered bool
+	// Stream asks --all listings that support it to print page by page.
+	Stream bool
+}
+
+// labelColors returns a loader for label colors keyed by lowercase name.
+// Labels are fetched once, through the cache, the first time a printer asks;
+// failures just leave labels uncolored.
+func (s *state) labelColors(ctx context.Context) func() map[string]string {
+	var colors map[string]string
+	return func() map[string]string {
+		if colors != nil {
+			return colors
+		}
+		colors = map[string]string{}
+		client, err := s.client()
+		if err != nil {
+			return colors
+		}
+		labels, err := client.ListLabelsAll(ctx, map[string]string{})
+		if err != nil {
+			return colors
+		}
+		for _, label := range labels {
+			colors[strings.ToLower(label.Name)] = label.Color
+		}
+		return colors
+	}
+}
+
+// userLocation returns a loader for the user's timezone. The user is
+// fetched once, through the cache, the first time a printer asks; failures
+// fall back to the local timezone.
+func (s *state) userLocation(ctx context.Context) func() *time.Location {
+	var loc *time.Location
+	return func() *time.Location {
+		if loc != nil {
+			return loc
+		}
+		loc = time.Local
+		client, err := s.client()
+		if err != nil {
+			return loc
+		}
+		if user, err := client.CachedUserInfo(ctx); err == nil {
+			loc = user.Location()
+		}
+		return loc
+	}
 }
 
 func (s *state) client() (*todi.Client, error) {
-	token := firstNonEmpty(os.Getenv("TODOIST_TOKEN"), s.Config.Token)
-	if token == "" {
-		return nil, errors.New("missing Todoist token: run 'todi auth login' or set TODOIST_TOKEN")
+	token, err := s.requireToken()
+	if err != nil {
+		return nil, err
+	}
+	client := todi.NewClient(s.APIBase, token, s.Verbose)
+	client.Retry = s.Retry
+	client.Offline = s.Offline
+	client.Match = s.Match
+	client.Choose = s.chooser()
+	if store := s.cacheStore(token); store != nil {
+		client.Cache = store
 	}
-	client := todi.NewClient(s.Config.APIBase, token, s.Verbose)
 	return client, nil
 }
 
+// token returns the API token, preferring TODOIST_TOKEN over the account's
+// stored token.
+func (s *state) token() (string, error) {
+	if token := os.Getenv("TODOIST_TOKEN"); token != "" || s.Account == nil {
+		return token, nil
+	}
+	return s.Account.ReadToken(s.configDir())
+}
+
+// requireToken is token for commands that cannot run without one.
+func (s *state) requireToken() (string, error) {
+	token, err := s.token()
+	if err != nil || token != "" {
+		return token, err
+	}
+	if s.Account == nil {
+		return "", errUnknownProfile(s.Profile)
+	}
+	return "", errMissingToken
+}
+
+// configDir holds files kept next to the config, such as the encrypted
+// credential store.
+func (s *state) configDir() string {
+	return filepath.Dir(s.ConfigPath)
+}
+
+// cacheStore returns the on-disk cache for token, or nil when caching is off.
+// Offline, cached entries are served regardless of age.
+func (s *state) cacheStore(token string) *cache.Store {
+	if s.NoCache || (s.CacheTTL <= 0 && !s.Offline) {
+		return nil
+	}
+	root, err := cache.DefaultRoot()
+	if err != nil {
+		return nil
+	}
+	store := cache.Open(root, s.APIBase, token, s.CacheTTL)
+	store.Refresh = s.RefreshCache
+	store.Stale = s.Offline
+	return store
+}
+
+// journal returns the offline write queue for the active account.
+func (s *state) journal() (*journal.Journal, error) {
+	token, err := s.requireToken()
+	if err != nil {
+		return nil, err
+	}
+	dir := filepath.Join(s.configDir(), "journal")
+	return journal.Open(dir, cache.AccountKey(s.APIBase, token)), nil
+}
+
 func parseGlobal(args []string, errOut io.Writer) (globalFlags, []string, int) {
 	fs := flag.NewFlagSet("todi", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
@@ -174,8 +389,17 @@ func parseGlobal(args []string, errOut io.Writer) (globalFlags, []string, int) {
 	fs.BoolVar(&flags.NoInput, "no-input", false, "Disable prompts")
 	fs.BoolVar(&flags.NoColor, "no-color", false, "Disable color")
 	fs.StringVar(&flags.ConfigPath, "config", "", "Config path")
+	fs.StringVar(&flags.Profile, "profile", "", "Profile name")
 	fs.StringVar(&flags.APIBase, "api-base", "", "API base URL")
 	fs.BoolVar(&flags.LabelCLI, "label-cli", false, "Add label 'cli' to created tasks")
+	fs.IntVar(&flags.Retries, "retries", -1, "Max API retries")
+	fs.StringVar(&flags.RetryWait, "retry-max-wait", "", "Max wait between API retries")
+	fs.BoolVar(&flags.NoCache, "no-cache", false, "Bypass the local cache")
+	fs.BoolVar(&flags.Refresh, "refresh", false, "Refetch and rewrite cached data")
+	fs.BoolVar(&flags.Offline, "offline", false, "Queue writes locally instead of sending them")
+	fs.StringVar(&flags.Format, "format", "", "Go template for each result")
+	fs.StringVar(&flags.Output, "output", "", "Output format (human|plain|json|csv|tsv|ndjson|yaml)")
+	fs.StringVar(&flags.Output, "o", "", "Output format")
 
 	if err := fs.Parse(args); err != nil {
 		if _, writeErr := fmt.Fprintln(errOut, "error:", err); writeErr != nil {
@@ -187,6 +411,79 @@ func parseGlobal(args []string, errOut io.Writer) (globalFlags, []string, int) {
 	return flags, fs.Args(), 0
 }
 
+func retryPolicy(globals globalFlags, cfg *config.Config) (todi.RetryPolicy, error) {
+	policy := todi.DefaultRetryPolicy()
+	if cfg.MaxRetries != nil {
+		policy.MaxRetries = *cfg.MaxRetries
+	}
+	if globals.Retries >= 0 {
+		policy.MaxRetries = globals.Retries
+	}
+	if policy.MaxRetries < 0 {
+		return policy, fmt.Errorf("retries must be >= 0")
+	}
+	maxWait := firstNonEmpty(globals.RetryWait, cfg.RetryMaxWait)
+	if maxWait != "" {
+		wait, err := parseRetryWait(maxWait)
+		if err != nil {
+			return policy, err
+		}
+		policy.MaxWait = wait
+		if policy.BaseWait > wait {
+			policy.BaseWait = wait
+		}
+	}
+	return policy, nil
+}
+
+func cacheTTL(cfg *config.Config) (time.Duration, error) {
+	if cfg.CacheTTL == "" {
+		return cache.DefaultTTL, nil
+	}
+	ttl, err := parseDuration(cfg.CacheTTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid cache_ttl: %s", cfg.CacheTTL)
+	}
+	return ttl, nil
+}
+
+// matchModes parses the match.<resource> config keys.
+func matchModes(cfg *config.Config) (map[string][]todi.MatchMode, error) {
+	match := map[string][]todi.MatchMode{}
+	for resource, value := range cfg.Match {
+		modes, err := todi.ParseMatchModes(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid match.%s: %w", resource, err)
+		}
+		match[resource] = modes
+	}
+	return match, nil
+}
+
+func parseRetryWait(value string) (time.Duration, error) {
+	wait, err := parseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid retry wait: %s", value)
+	}
+	return wait, nil
+}
+
+// parseDuration accepts Go durations or bare seconds.
+func parseDuration(value string) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+	if seconds, err := strconv.Atoi(value); err == nil {
+		value = strconv.Itoa(seconds) + "s"
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if d < 0 {
+		return 0, fmt.Errorf("negative duration: %s", value)
+	}
+	return d, nil
+}
+
 func envOrDefault(key, def string) string {
 	if val := os.Getenv(key); val != "" {
 		return val
@@ -203,17 +500,40 @@ func firstNonEmpty(values ...string) string {
 	return ""
 }
 
-func parseOutputMode(json, plain bool) (outputMode, error) {
+// parseOutputMode resolves --json, --plain and --output. Formats rendered
+// from JSON output are returned separately and run in JSON mode.
+func parseOutputMode(json, plain bool, output string) (outputMode, string, error) {
 	if json && plain {
-		return modeHuman, fmt.Errorf("cannot use --json and --plain together")
+		return modeHuman, "", fmt.Errorf("cannot use --json and --plain together")
 	}
-	if json {
-		return modeJSON, nil
+	output = strings.ToLower(strings.TrimSpace(output))
+	switch {
+	case output == "":
+	case json && output != outputJSON, plain && output != outputPlain:
+		return modeHuman, "", fmt.Errorf("cannot combine --output %s with --json or --plain", output)
 	}
-	if plain {
-		return modePlain, nil
+	switch output {
+	case "":
+		if json {
+			return modeJSON, "", nil
+		}
+		if plain {
+			return modePlain, "", nil
+		}
+		return modeHuman, "", nil
+	case outputHuman:
+		return modeHuman, "", nil
+	case outputWide:
+		return modeWide, "", nil
+	case outputPlain:
+		return modePlain, "", nil
+	case outputJSON:
+		return modeJSON, "", nil
+	case outputCSV, outputTSV, outputNDJSON, outputYAML:
+		return modeJSON, output, nil
+	default:
+		return modeHuman, "", fmt.Errorf("unknown output format: %s (want human, wide, plain, json, csv, tsv, ndjson or yaml)", output)
 	}
-	return modeHuman, nil
 }
 
 func joinArgs(args []string) string {
@@ -222,7 +542,7 @@ func joinArgs(args []string) string {
 
 func isTaskSubcommand(arg string) bool {
 	switch arg {
-	case "list", "get", "add", "update", "close", "reopen", "delete", "quick":
+	case "list", "get", "add", "update", "close", "reopen", "delete", "move", "quick":
 		return true
 	default:
 		return false
@@ -3,11 +3,18 @@ package app
 import (
 	"bufio"
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"io"
+	"net/http"
 	"os"
+	"sort"
 	"strings"
+	"time"
+
+	"github.com/mattjefferson/todi/internal/config"
+	"github.com/mattjefferson/todi/internal/todi"
 )
 
 func runAuth(ctx context.Context, state *state, args []string) int {
@@ -21,16 +28,16 @@ func runAuth(ctx context.Context, state *state, args []string) int {
 	case "logout":
 		return runAuthLogout(state)
 	case "status":
-		return runAuthStatus(state)
+		return runAuthStatus(ctx, state, args[1:])
+	case "migrate":
+		return runAuthMigrate(state, args[1:])
 	case "-h", "--help", "help":
 		printAuthUsage(state.Out)
 		return 0
 	default:
-		if _, err := fmt.Fprintln(state.Err, "error: unknown auth command:", args[0]); err != nil {
-			return 2
-		}
+		code := reportError(state, usageErrorf("unknown auth command: %s", args[0]))
 		printAuthUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -38,87 +45,320 @@ func runAuthLogin(ctx context.Context, state *state, args []string) int {
 	fs := flag.NewFlagSet("todi auth login", flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	var help bool
+	var profile string
+	var useOAuth bool
+	var noVerify bool
+	var oauth oauthFlags
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
+	fs.StringVar(&profile, "profile", "", "Profile to save the token to")
+	fs.BoolVar(&noVerify, "no-verify", false, "Save the token without checking it")
+	fs.BoolVar(&useOAuth, "oauth", false, "Log in through the browser with OAuth")
+	fs.StringVar(&oauth.AuthBase, "auth-base", "", "OAuth server base URL")
+	fs.StringVar(&oauth.ClientID, "client-id", "", "OAuth client ID")
+	fs.StringVar(&oauth.ClientSecret, "client-secret", "", "OAuth client secret")
+	fs.StringVar(&oauth.Scopes, "scopes", "", "OAuth scopes (comma-separated)")
+	fs.IntVar(&oauth.Port, "port", 0, "Loopback port for the OAuth redirect")
+	fs.BoolVar(&oauth.NoBrowser, "no-browser", false, "Print the authorize URL without opening a browser")
+	fs.DurationVar(&oauth.Timeout, "timeout", 5*time.Minute, "How long to wait for the OAuth redirect")
 	if err := fs.Parse(args); err != nil {
-		if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-			return 2
-		}
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printAuthUsage(state.Out)
 		return 0
 	}
-	if state.NoInput || !isTTY(os.Stdin) {
-		if _, err := fmt.Fprintln(state.Err, "error: login requires TTY (disable --no-input)"); err != nil {
-			return 2
-		}
-		return 2
-	}
-	reader := bufio.NewReader(os.Stdin)
-	if _, err := fmt.Fprint(state.Err, "Todoist token: "); err != nil {
-		return 1
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
 	}
-	token, err := reader.ReadString('\n')
-	if err != nil {
-		if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-			return 1
+
+	var token, scopes string
+	if useOAuth {
+		granted, err := oauthLogin(ctx, state, oauth)
+		if err != nil {
+			return reportError(state, err)
 		}
-		return 1
-	}
-	token = strings.TrimSpace(token)
-	if token == "" {
-		if _, err := fmt.Fprintln(state.Err, "error: token required"); err != nil {
-			return 2
+		token, scopes = granted.AccessToken, granted.Scope
+	} else {
+		var err error
+		if token, err = promptToken(state); err != nil {
+			return reportError(state, err)
 		}
-		return 2
 	}
 
-	state.Config.Token = token
-	if err := state.Config.Save(state.ConfigPath); err != nil {
-		if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-			return 1
+	name := firstNonEmpty(profile, state.Profile)
+	account := state.Config.Account(name)
+	var user *todi.User
+	if !noVerify {
+		info, err := verifyToken(ctx, state, state.accountAPIBase(name, account), token)
+		if err != nil {
+			return reportError(state, err)
 		}
-		return 1
+		user = &info
+	}
+	if account == nil {
+		account = state.Config.AddProfile(name)
 	}
-	if _, err := fmt.Fprintln(state.Out, "token saved"); err != nil {
+	store := config.ResolveStore(state.Config.CredentialStore)
+	previous, err := account.SaveToken(token, store, state.configDir())
+	if err != nil {
+		return reportError(state, err)
+	}
+	account.Scopes = scopes
+	if err := state.Config.Save(state.ConfigPath); err != nil {
+		return reportError(state, err)
+	}
+	deleteOldToken(state, previous)
+	where := store
+	if name != "" {
+		where = "profile " + name + ", " + store
+	}
+	saved := "token saved"
+	if user != nil {
+		saved += " for " + userLabel(*user)
+	}
+	if _, err := fmt.Fprintf(state.Out, "%s (%s)\n", saved, where); err != nil {
 		return 1
 	}
-	_ = ctx
 	return 0
 }
 
+// verifyToken checks token against the API, bypassing the cache.
+func verifyToken(ctx context.Context, state *state, apiBase, token string) (todi.User, error) {
+	client := todi.NewClient(apiBase, token, state.Verbose)
+	client.Retry = state.Retry
+	client.Offline = state.Offline
+	user, err := client.GetUserInfo(ctx)
+	if err == nil {
+		return user, nil
+	}
+	var apiErr *todi.APIError
+	if errors.As(err, &apiErr) && (apiErr.StatusCode == http.StatusUnauthorized || apiErr.StatusCode == http.StatusForbidden) {
+		return todi.User{}, fmt.Errorf("token rejected: %w", err)
+	}
+	return todi.User{}, fmt.Errorf("verify token: %w", err)
+}
+
+// accountAPIBase is the API base for the named account, which need not be
+// the selected one. --api-base applies to every account.
+func (s *state) accountAPIBase(name string, account *config.Profile) string {
+	if name == s.Profile || s.Sources["api_base"] == "flag --api-base" {
+		return s.APIBase
+	}
+	if account != nil && account.APIBase != "" {
+		return account.APIBase
+	}
+	return envOrDefault("TODOIST_API_BASE", defaultAPIBase)
+}
+
+func userLabel(user todi.User) string {
+	if user.FullName == "" {
+		return user.Email
+	}
+	return fmt.Sprintf("%s <%s>", user.FullName, user.Email)
+}
+
+// promptToken reads a pasted API token from the terminal.
+func promptToken(state *state) (string, error) {
+	if state.NoInput || !isTTY(os.Stdin) {
+		return "", usageErrorf("login requires TTY (disable --no-input, or use --oauth)")
+	}
+	if _, err := fmt.Fprint(state.Err, "Todoist token: "); err != nil {
+		return "", err
+	}
+	token, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return "", err
+	}
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return "", usageErrorf("token required")
+	}
+	return token, nil
+}
+
 func runAuthLogout(state *state) int {
-	state.Config.Token = ""
+	if state.Account == nil {
+		return reportError(state, errUnknownProfile(state.Profile))
+	}
+	previous := state.Account.ClearToken()
 	if err := state.Config.Save(state.ConfigPath); err != nil {
-		if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-			return 1
-		}
-		return 1
+		return reportError(state, err)
 	}
+	deleteOldToken(state, previous)
 	if _, err := fmt.Fprintln(state.Out, "token cleared"); err != nil {
 		return 1
 	}
 	return 0
 }
 
-func runAuthStatus(state *state) int {
-	envToken := os.Getenv("TODOIST_TOKEN")
-	if envToken != "" {
-		if _, err := fmt.Fprintln(state.Out, "token set (TODOIST_TOKEN)"); err != nil {
-			return 1
-		}
+// deleteOldToken removes a replaced token from its credential store. The
+// config no longer refers to it, so a failure only leaves a stale entry.
+func deleteOldToken(state *state, ref string) {
+	if err := config.DeleteToken(ref, state.configDir()); err != nil {
+		writeLine(state.Err, "warning: "+err.Error())
+	}
+}
+
+// errTokenMissing is the auth status error when no token is stored.
+const errTokenMissing = "token missing"
+
+// authStatus is the result of todi auth status.
+type authStatus struct {
+	Profile string     `json:"profile,omitempty"`
+	Source  string     `json:"source"`
+	Store   string     `json:"store,omitempty"`
+	Scopes  string     `json:"scopes,omitempty"`
+	Valid   bool       `json:"valid"`
+	User    *todi.User `json:"user,omitempty"`
+	Error   string     `json:"error,omitempty"`
+}
+
+func runAuthStatus(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi auth status", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printAuthUsage(state.Out)
 		return 0
 	}
-	if state.Config.Token != "" {
-		if _, err := fmt.Fprintln(state.Out, "token set (config)"); err != nil {
-			return 1
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+
+	status := authStatus{Profile: state.Profile}
+	token := os.Getenv("TODOIST_TOKEN")
+	if token != "" {
+		status.Source = "env TODOIST_TOKEN"
+	} else {
+		if state.Account == nil {
+			return reportError(state, errUnknownProfile(state.Profile))
 		}
+		var err error
+		if token, err = state.Account.ReadToken(state.configDir()); err != nil {
+			return reportError(state, err)
+		}
+		status.Source = "config"
+		if state.Profile != "" {
+			status.Source = "profile " + state.Profile
+		}
+		status.Store = state.Account.TokenStore()
+		status.Scopes = state.Account.Scopes
+	}
+	if token == "" {
+		status.Error = errTokenMissing
+		if err := printAuthStatus(state.Out, status, state.Mode); err != nil {
+			return reportError(state, err)
+		}
+		return exitAuth
+	}
+
+	code := 0
+	user, err := verifyToken(ctx, state, state.APIBase, token)
+	if err != nil {
+		status.Error = err.Error()
+		code = classifyError(err).ExitCode
+	} else {
+		status.Valid = true
+		status.User = &user
+	}
+	if err := printAuthStatus(state.Out, status, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return code
+}
+
+// migrateResult reports one account of todi auth migrate.
+type migrateResult struct {
+	Profile  string `json:"profile"`
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Migrated bool   `json:"migrated"`
+	Error    string `json:"error,omitempty"`
+}
+
+func runAuthMigrate(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi auth migrate", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var to string
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.StringVar(&to, "to", "", "Target credential store")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printAuthUsage(state.Out)
 		return 0
 	}
-	if _, err := fmt.Fprintln(state.Out, "token missing"); err != nil {
-		return 1
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if to != "" && !config.ValidStore(to) {
+		return reportError(state, usageErrorf("unknown credential store: %s", to))
+	}
+	target := config.ResolveStore(firstNonEmpty(to, state.Config.CredentialStore))
+
+	cfg := state.Config
+	names := make([]string, 0, len(cfg.Profiles)+1)
+	if cfg.Profile.TokenStore() != "" {
+		names = append(names, "")
+	}
+	for name := range cfg.Profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	dir := state.configDir()
+	results := []migrateResult{}
+	var previous []string
+	failed := false
+	for _, name := range names {
+		account := cfg.Account(name)
+		from := account.TokenStore()
+		if from == "" {
+			continue
+		}
+		result := migrateResult{Profile: firstNonEmpty(name, config.DefaultProfile), From: from, To: target}
+		if from != target {
+			old, err := migrateToken(account, target, dir)
+			if err != nil {
+				result.Error = err.Error()
+				failed = true
+			} else {
+				result.Migrated = true
+				previous = append(previous, old)
+			}
+		}
+		results = append(results, result)
+	}
+	if err := cfg.Save(state.ConfigPath); err != nil {
+		return reportError(state, err)
+	}
+	for _, ref := range previous {
+		deleteOldToken(state, ref)
+	}
+	if err := printMigrateResults(state.Out, results, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	if failed {
+		return exitError
+	}
+	return 0
+}
+
+// migrateToken moves the account's token to the target store and returns
+// the reference to the old copy.
+func migrateToken(account *config.Profile, target, dir string) (string, error) {
+	token, err := account.ReadToken(dir)
+	if err != nil {
+		return "", err
 	}
-	return 3
+	return account.SaveToken(token, target, dir)
 }
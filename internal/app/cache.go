@@ -0,0 +1,133 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/mattjefferson/todi/internal/cache"
+)
+
+func runCache(_ context.Context, state *state, args []string) int {
+	if len(args) == 0 {
+		printCacheUsage(state.Out)
+		return 2
+	}
+	switch args[0] {
+	case "status":
+		return runCacheStatus(state, args[1:])
+	case "clear":
+		return runCacheClear(state, args[1:])
+	case "-h", "--help", "help":
+		printCacheUsage(state.Out)
+		return 0
+	default:
+		code := reportError(state, usageErrorf("unknown cache command: %s", args[0]))
+		printCacheUsage(state.Err)
+		return code
+	}
+}
+
+func runCacheStatus(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi cache status", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var all bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&all, "all", false, "Show every cached account")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printCacheUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+
+	root, err := cache.DefaultRoot()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	var accounts []cache.Account
+	if all {
+		accounts, err = cache.Accounts(root, state.CacheTTL)
+		if err != nil {
+			return reportError(state, err)
+		}
+	} else {
+		token, err := state.requireToken()
+		if err != nil {
+			return reportError(state, err)
+		}
+		store := cache.Open(root, state.APIBase, token, state.CacheTTL)
+		entries, err := store.Entries()
+		if err != nil {
+			return reportError(state, err)
+		}
+		accounts = []cache.Account{{Dir: store.Dir, APIBase: store.APIBase, Entries: entries}}
+	}
+
+	status := cacheStatus{
+		Root:     root,
+		Enabled:  !state.NoCache && state.CacheTTL > 0,
+		TTL:      state.CacheTTL.String(),
+		Accounts: accounts,
+	}
+	if err := printCacheStatus(state.Out, status, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runCacheClear(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi cache clear", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var all bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&all, "all", false, "Clear every cached account")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printCacheUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+
+	root, err := cache.DefaultRoot()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	if all {
+		if err := cache.ClearAll(root); err != nil {
+			return reportError(state, err)
+		}
+		if _, err := fmt.Fprintln(state.Out, "cache cleared"); err != nil {
+			return 1
+		}
+		return 0
+	}
+
+	token, err := state.requireToken()
+	if err != nil {
+		return reportError(state, err)
+	}
+	store := cache.Open(root, state.APIBase, token, state.CacheTTL)
+	if err := store.Clear(); err != nil {
+		return reportError(state, err)
+	}
+	if _, err := fmt.Fprintln(state.Out, "cache cleared"); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,43 @@
+package app
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+// chooser returns a prompt that settles ambiguous name lookups, or nil when
+// todi cannot ask (--no-input or no TTY), in which case the lookup fails with
+// its candidates listed.
+func (s *state) chooser() todi.Chooser {
+	if s.NoInput || !isTTY(os.Stdin) {
+		return nil
+	}
+	return func(lookupErr *todi.LookupError) (int, error) {
+		if _, err := fmt.Fprintf(s.Err, "Several %ss match %q:\n", lookupErr.Resource, lookupErr.Query); err != nil {
+			return 0, err
+		}
+		for i, candidate := range lookupErr.Candidates {
+			if _, err := fmt.Fprintf(s.Err, "  %d) %s\n", i+1, candidate); err != nil {
+				return 0, err
+			}
+		}
+		if _, err := fmt.Fprintf(s.Err, "Choose [1-%d]: ", len(lookupErr.Candidates)); err != nil {
+			return 0, err
+		}
+		line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return 0, err
+		}
+		choice, err := strconv.Atoi(strings.TrimSpace(line))
+		if err != nil || choice < 1 || choice > len(lookupErr.Candidates) {
+			return 0, errors.New("aborted")
+		}
+		return choice - 1, nil
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/mattjefferson/todi/internal/journal"
 	"github.com/mattjefferson/todi/internal/todi"
 )
 
@@ -50,9 +51,9 @@ func runComment(ctx context.Context, state *state, args []string) int {
 		printCommentUsage(state.Out)
 		return 0
 	default:
-		writeLine(state.Err, "error: unknown comment command:", args[0])
+		code := reportError(state, usageErrorf("unknown comment command: %s", args[0]))
 		printCommentUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -67,6 +68,7 @@ func runCommentList(ctx context.Context, state *state, args []string) int {
 	var limit int
 	var cursor string
 	var all bool
+	var view listView
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.StringVar(&taskTitle, "task", "", "Task title (exact match)")
@@ -76,29 +78,29 @@ func runCommentList(ctx context.Context, state *state, args []string) int {
 	fs.IntVar(&limit, "limit", 50, "Max comments per page (1-200)")
 	fs.StringVar(&cursor, "cursor", "", "Pagination cursor")
 	fs.BoolVar(&all, "all", false, "Fetch all pages")
+	view.register(fs)
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printCommentUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) > 0 {
-		writeLine(state.Err, "error: unexpected arguments")
-		return 2
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if _, _, err := view.parse(commentView); err != nil {
+		return reportError(state, usageError{err})
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	key, value, err := resolveCommentScope(ctx, client, taskTitle, taskID, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, err)
 	}
 
 	params := map[string]string{key: value}
@@ -112,32 +114,38 @@ func runCommentList(ctx context.Context, state *state, args []string) int {
 	if all {
 		comments, err := client.ListCommentsAll(ctx, params)
 		if err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
-		if err := printComments(state.Out, comments, state.Mode); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+		if view.active() {
+			err = view.print(ctx, state, client, commentView, comments, nil)
+		} else {
+			err = printComments(state.Out, comments, state.Mode)
+		}
+		if err != nil {
+			return reportError(state, err)
 		}
 		return 0
 	}
 
 	comments, next, err := client.ListComments(ctx, params)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+	if view.active() {
+		if err := view.print(ctx, state, client, commentView, comments, &next); err != nil {
+			return reportError(state, err)
+		}
+		return 0
 	}
 	if state.Mode == modeJSON {
 		payload := map[string]any{"results": comments, "next_cursor": next}
 		if err := printJSON(state.Out, payload); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printComments(state.Out, comments, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -149,8 +157,7 @@ func runCommentGet(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printCommentUsage(state.Out)
@@ -158,24 +165,20 @@ func runCommentGet(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: comment ID required")
-		return 2
+		return reportError(state, usageErrorf("comment ID required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	comment, err := client.GetComment(ctx, identifier)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := printComment(state.Out, comment, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -201,8 +204,7 @@ func runCommentAdd(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&uploadPath, "file", "", "Upload file attachment")
 	fs.StringVar(&uploadName, "file-name", "", "Override upload file name")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printCommentUsage(state.Out)
@@ -210,24 +212,31 @@ func runCommentAdd(ctx context.Context, state *state, args []string) int {
 	}
 	content := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if content == "" {
-		writeLine(state.Err, "error: content required")
-		return 2
+		return reportError(state, usageErrorf("content required"))
 	}
 	if uploadName != "" && uploadPath == "" {
-		writeLine(state.Err, "error: --file-name requires --file")
-		return 2
+		return reportError(state, usageErrorf("--file-name requires --file"))
+	}
+	if uploadPath != "" && state.Offline {
+		return reportError(state, usageErrorf("cannot use --file with --offline"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+
+	if taskTitle != "" && taskID == "" && state.Offline {
+		taskID, err = resolveQueuedTaskID(ctx, state, client, taskTitle, false)
+		if err != nil {
+			return reportError(state, err)
+		}
+		taskTitle = ""
 	}
 
 	key, value, err := resolveCommentScope(ctx, client, taskTitle, taskID, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, err)
 	}
 
 	body := map[string]any{
@@ -241,8 +250,7 @@ func runCommentAdd(ctx context.Context, state *state, args []string) int {
 		}
 		upload, _, err := client.UploadFile(ctx, uploadPath, uploadName, uploadProjectID)
 		if err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		body["attachment"] = fileAttachmentFromUpload(upload)
 	}
@@ -250,21 +258,22 @@ func runCommentAdd(ctx context.Context, state *state, args []string) int {
 		body["uids_to_notify"] = notify
 	}
 
+	if state.Offline {
+		return queueWrite(state, journal.OpCommentAdd, journal.NewTempID(), body)
+	}
+
 	comment, raw, err := client.CreateComment(ctx, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printComment(state.Out, comment, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -278,8 +287,7 @@ func runCommentUpdate(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.StringVar(&content, "content", "", "Comment content")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printCommentUsage(state.Out)
@@ -287,30 +295,28 @@ func runCommentUpdate(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: comment ID required")
-		return 2
+		return reportError(state, usageErrorf("comment ID required"))
 	}
 	if content == "" {
-		writeLine(state.Err, "error: content required")
-		return 2
+		return reportError(state, usageErrorf("content required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	body := map[string]any{"content": content}
+	if state.Offline {
+		return queueWrite(state, journal.OpCommentUpdate, identifier, body)
+	}
 	comment, raw, err := client.UpdateComment(ctx, identifier, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -319,8 +325,7 @@ func runCommentUpdate(ctx context.Context, state *state, args []string) int {
 		return 0
 	}
 	if err := printComment(state.Out, comment, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -334,8 +339,7 @@ func runCommentDelete(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.BoolVar(&force, "force", false, "Skip confirmation")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printCommentUsage(state.Out)
@@ -343,30 +347,29 @@ func runCommentDelete(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: comment ID required")
-		return 2
+		return reportError(state, usageErrorf("comment ID required"))
 	}
 
 	if err := confirmDelete(state, "comment", identifier, force); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
+	}
+
+	if state.Offline {
+		return queueWrite(state, journal.OpCommentDelete, identifier, nil)
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	raw, err := client.DeleteComment(ctx, identifier)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -378,15 +381,15 @@ func runCommentDelete(ctx context.Context, state *state, args []string) int {
 
 func resolveCommentScope(ctx context.Context, client *todi.Client, taskTitle, taskID, projectName, projectID string) (string, string, error) {
 	if taskTitle != "" && taskID != "" {
-		return "", "", fmt.Errorf("cannot use --task and --task-id together")
+		return "", "", usageErrorf("cannot use --task and --task-id together")
 	}
 	if projectName != "" && projectID != "" {
-		return "", "", fmt.Errorf("cannot use --project and --project-id together")
+		return "", "", usageErrorf("cannot use --project and --project-id together")
 	}
 	hasTask := taskTitle != "" || taskID != ""
 	hasProject := projectName != "" || projectID != ""
 	if hasTask && hasProject {
-		return "", "", fmt.Errorf("use either task or project, not both")
+		return "", "", usageErrorf("use either task or project, not both")
 	}
 	if taskID != "" {
 		return "task_id", taskID, nil
@@ -397,7 +400,7 @@ func resolveCommentScope(ctx context.Context, client *todi.Client, taskTitle, ta
 			return "", "", err
 		}
 		if task.ID == "" {
-			return "", "", fmt.Errorf("task not found: %s", taskTitle)
+			return "", "", &todi.LookupError{Resource: "task", Field: "title", Query: taskTitle, Err: todi.ErrNotFound}
 		}
 		return "task_id", task.ID, nil
 	}
@@ -411,7 +414,7 @@ func resolveCommentScope(ctx context.Context, client *todi.Client, taskTitle, ta
 		}
 		return "project_id", projectIDValue, nil
 	}
-	return "", "", fmt.Errorf("task or project required")
+	return "", "", usageErrorf("task or project required")
 }
 
 func fileAttachmentFromUpload(upload todi.Upload) *todi.FileAttachment {
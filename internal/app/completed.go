@@ -0,0 +1,203 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+const (
+	defaultCompletedWindow = 7 * 24 * time.Hour
+	completedTimeLayout    = "2006-01-02T15:04:05Z"
+	// maxCompletedMonths is the longest range the API accepts.
+	maxCompletedMonths = 3
+)
+
+func runCompleted(ctx context.Context, state *state, args []string) int {
+	if len(args) == 0 {
+		printCompletedUsage(state.Out)
+		return 2
+	}
+	switch args[0] {
+	case "list":
+		return runCompletedList(ctx, state, args[1:])
+	case "-h", "--help", "help":
+		printCompletedUsage(state.Out)
+		return 0
+	default:
+		code := reportError(state, usageErrorf("unknown completed command: %s", args[0]))
+		printCompletedUsage(state.Err)
+		return code
+	}
+}
+
+func runCompletedList(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi completed list", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var since string
+	var until string
+	var projectName string
+	var projectID string
+	var sectionName string
+	var sectionID string
+	var parentTitle string
+	var parentID string
+	var limit int
+	var cursor string
+	var all bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.StringVar(&since, "since", "", "Start of range (YYYY-MM-DD or RFC3339)")
+	fs.StringVar(&until, "until", "", "End of range (YYYY-MM-DD or RFC3339)")
+	fs.StringVar(&projectName, "project", "", "Project title (exact match)")
+	fs.StringVar(&projectID, "project-id", "", "Project ID")
+	fs.StringVar(&sectionName, "section", "", "Section name (exact match)")
+	fs.StringVar(&sectionID, "section-id", "", "Section ID")
+	fs.StringVar(&parentTitle, "parent", "", "Parent task title (exact match)")
+	fs.StringVar(&parentID, "parent-id", "", "Parent task ID")
+	fs.IntVar(&limit, "limit", 50, "Max tasks per page (1-200)")
+	fs.StringVar(&cursor, "cursor", "", "Pagination cursor")
+	fs.BoolVar(&all, "all", false, "Fetch all pages")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printCompletedUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if sectionName != "" && sectionID != "" {
+		return reportError(state, usageErrorf("cannot use --section and --section-id together"))
+	}
+	if parentTitle != "" && parentID != "" {
+		return reportError(state, usageErrorf("cannot use --parent and --parent-id together"))
+	}
+
+	now := time.Now()
+	untilTime := now
+	if until != "" {
+		parsed, err := parseRangeTime(until, true)
+		if err != nil {
+			return reportError(state, usageErrorf("invalid --until: %s", until))
+		}
+		untilTime = parsed
+	}
+	sinceTime := untilTime.Add(-defaultCompletedWindow)
+	if since != "" {
+		parsed, err := parseRangeTime(since, false)
+		if err != nil {
+			return reportError(state, usageErrorf("invalid --since: %s", since))
+		}
+		sinceTime = parsed
+	}
+	if !sinceTime.Before(untilTime) {
+		return reportError(state, usageErrorf("--since must be before --until"))
+	}
+	if untilTime.After(sinceTime.AddDate(0, maxCompletedMonths, 0)) {
+		return reportError(state, usageErrorf("--since and --until must be at most %d months apart", maxCompletedMonths))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if sectionName != "" {
+		sectionID, err = client.FindSectionIDByName(ctx, sectionName, projectIDValue)
+		if err != nil {
+			return reportError(state, err)
+		}
+	}
+
+	params := map[string]string{
+		"since":      sinceTime.UTC().Format(completedTimeLayout),
+		"until":      untilTime.UTC().Format(completedTimeLayout),
+		"project_id": projectIDValue,
+		"section_id": sectionID,
+		"parent_id":  parentID,
+	}
+	if parentTitle != "" {
+		parentID, err := resolveCompletedParentID(ctx, client, parentTitle, params)
+		if err != nil {
+			return reportError(state, err)
+		}
+		params["parent_id"] = parentID
+	}
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	if cursor != "" {
+		params["cursor"] = cursor
+	}
+
+	if all {
+		tasks, err := client.ListCompletedTasksAll(ctx, params)
+		if err != nil {
+			return reportError(state, err)
+		}
+		if err := printTasks(state.Out, tasks, state.Mode); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+
+	tasks, next, err := client.ListCompletedTasks(ctx, params)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if state.Mode == modeJSON {
+		setColumns(state.Out, taskView.tagKeys())
+		payload := map[string]any{"results": withTaskURLs(tasks), "next_cursor": next}
+		if err := printJSON(state.Out, payload); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+	if err := printTasks(state.Out, tasks, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+// resolveCompletedParentID finds a parent task by title among active tasks
+// or, failing that, among the tasks completed in the listed range.
+func resolveCompletedParentID(ctx context.Context, client *todi.Client, title string, params map[string]string) (string, error) {
+	parentID, err := resolveTaskID(ctx, client, title, false)
+	if !errors.Is(err, todi.ErrNotFound) {
+		return parentID, err
+	}
+	task, err := client.FindCompletedTaskByContent(ctx, title, params)
+	if err != nil {
+		return "", err
+	}
+	return task.ID, nil
+}
+
+// parseRangeTime parses RFC3339 or a local YYYY-MM-DD date. Bare dates mean
+// the start of the day, or its last second when endOfDay is set.
+func parseRangeTime(value string, endOfDay bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation(time.DateOnly, value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("expected YYYY-MM-DD or RFC3339: %w", err)
+	}
+	if endOfDay {
+		return day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return day, nil
+}
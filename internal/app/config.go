@@ -6,7 +6,12 @@ import (
 	"fmt"
 	"io"
 	"os"
+	"slices"
+	"strconv"
 	"strings"
+
+	"github.com/mattjefferson/todi/internal/config"
+	"github.com/mattjefferson/todi/internal/todi"
 )
 
 func runConfig(_ context.Context, state *state, args []string) int {
@@ -25,16 +30,14 @@ func runConfig(_ context.Context, state *state, args []string) int {
 		}
 		return 0
 	case "view":
-		return runConfigView(state)
+		return runConfigView(state, args[1:])
 	case "-h", "--help", "help":
 		printConfigUsage(state.Out)
 		return 0
 	default:
-		if _, err := fmt.Fprintln(state.Err, "error: unknown config command:", args[0]); err != nil {
-			return 2
-		}
+		code := reportError(state, usageErrorf("unknown config command: %s", args[0]))
 		printConfigUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -45,37 +48,90 @@ func runConfigGet(state *state, args []string) int {
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	if err := fs.Parse(args); err != nil {
-		if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-			return 2
-		}
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printConfigUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) == 0 {
-		if _, err := fmt.Fprintln(state.Err, "error: key required"); err != nil {
-			return 2
-		}
-		return 2
+		return reportError(state, usageErrorf("key required"))
 	}
 	key := strings.ToLower(fs.Args()[0])
+	if name, ok := strings.CutPrefix(key, "format."); ok {
+		if _, err := fmt.Fprintln(state.Out, state.Config.Formats[name]); err != nil {
+			return 1
+		}
+		return 0
+	}
+	if resource, ok := strings.CutPrefix(key, "match."); ok {
+		if _, err := fmt.Fprintln(state.Out, state.Config.Match[resource]); err != nil {
+			return 1
+		}
+		return 0
+	}
+	if isAccountKey(key) && state.Account == nil {
+		return reportError(state, errUnknownProfile(state.Profile))
+	}
 	switch key {
 	case "token":
-		if _, err := fmt.Fprintln(state.Out, state.Config.Token); err != nil {
+		token, err := state.Account.ReadToken(state.configDir())
+		if err != nil {
+			return reportError(state, err)
+		}
+		if _, err := fmt.Fprintln(state.Out, token); err != nil {
 			return 1
 		}
 	case "api_base":
-		if _, err := fmt.Fprintln(state.Out, state.Config.APIBase); err != nil {
+		if _, err := fmt.Fprintln(state.Out, state.APIBase); err != nil {
 			return 1
 		}
 	case "default_project":
-		if _, err := fmt.Fprintln(state.Out, state.Config.Project); err != nil {
+		if _, err := fmt.Fprintln(state.Out, state.Account.Project); err != nil {
 			return 1
 		}
 	case "default_labels":
-		if _, err := fmt.Fprintln(state.Out, state.Config.Labels); err != nil {
+		if _, err := fmt.Fprintln(state.Out, state.Account.Labels); err != nil {
+			return 1
+		}
+	case "max_retries":
+		value := ""
+		if state.Config.MaxRetries != nil {
+			value = strconv.Itoa(*state.Config.MaxRetries)
+		}
+		if _, err := fmt.Fprintln(state.Out, value); err != nil {
+			return 1
+		}
+	case "retry_max_wait":
+		if _, err := fmt.Fprintln(state.Out, state.Config.RetryMaxWait); err != nil {
+			return 1
+		}
+	case "cache_ttl":
+		if _, err := fmt.Fprintln(state.Out, state.Config.CacheTTL); err != nil {
+			return 1
+		}
+	case "color":
+		if _, err := fmt.Fprintln(state.Out, state.Config.Color); err != nil {
+			return 1
+		}
+	case "credential_store":
+		if _, err := fmt.Fprintln(state.Out, state.Config.CredentialStore); err != nil {
+			return 1
+		}
+	case "auth_base":
+		if _, err := fmt.Fprintln(state.Out, state.Config.AuthBase); err != nil {
+			return 1
+		}
+	case "oauth_client_id":
+		if _, err := fmt.Fprintln(state.Out, state.Config.OAuthClientID); err != nil {
+			return 1
+		}
+	case "oauth_client_secret":
+		if _, err := fmt.Fprintln(state.Out, state.Config.OAuthClientSecret); err != nil {
+			return 1
+		}
+	case "oauth_scopes":
+		if _, err := fmt.Fprintln(state.Out, state.Config.OAuthScopes); err != nil {
 			return 1
 		}
 	case "label_cli":
@@ -89,10 +145,7 @@ func runConfigGet(state *state, args []string) int {
 			}
 		}
 	default:
-		if _, err := fmt.Fprintln(state.Err, "error: unknown key:", key); err != nil {
-			return 2
-		}
-		return 2
+		return reportError(state, usageErrorf("unknown key: %s", key))
 	}
 	return 0
 }
@@ -104,70 +157,179 @@ func runConfigSet(state *state, args []string) int {
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	if err := fs.Parse(args); err != nil {
-		if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-			return 2
-		}
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printConfigUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) < 2 {
-		if _, err := fmt.Fprintln(state.Err, "error: key and value required"); err != nil {
-			return 2
-		}
-		return 2
+		return reportError(state, usageErrorf("key and value required"))
 	}
 	key := strings.ToLower(fs.Args()[0])
 	value := strings.Join(fs.Args()[1:], " ")
 	if key == "token" {
-		if _, err := fmt.Fprintln(state.Err, "error: set token via 'todi auth login'"); err != nil {
-			return 2
+		return reportError(state, usageErrorf("set token via 'todi auth login'"))
+	}
+	switch {
+	case strings.HasPrefix(key, "format."):
+		if err := setFormat(state, strings.TrimPrefix(key, "format."), value); err != nil {
+			return reportError(state, usageError{err})
+		}
+	case strings.HasPrefix(key, "match."):
+		if err := setMatch(state, strings.TrimPrefix(key, "match."), value); err != nil {
+			return reportError(state, usageError{err})
+		}
+	default:
+		if code := setConfigKey(state, key, value); code != 0 {
+			return code
 		}
-		return 2
+	}
+	if err := state.Config.Save(state.ConfigPath); err != nil {
+		return reportError(state, err)
+	}
+	if _, err := fmt.Fprintln(state.Out, "saved"); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// setFormat saves a named --format template; an empty value removes it.
+func setFormat(state *state, name, value string) error {
+	if name == "" {
+		return fmt.Errorf("format name required")
+	}
+	if value == "" {
+		delete(state.Config.Formats, name)
+		return nil
+	}
+	if _, err := parseFormat(value, state.Config); err != nil {
+		return err
+	}
+	if state.Config.Formats == nil {
+		state.Config.Formats = map[string]string{}
+	}
+	state.Config.Formats[name] = value
+	return nil
+}
+
+// setMatch saves the name lookup modes of a resource; an empty value
+// restores the default.
+func setMatch(state *state, resource, value string) error {
+	if !slices.Contains(todi.MatchResources, resource) {
+		return fmt.Errorf("unknown match resource: %s (use %s)", resource, strings.Join(todi.MatchResources, ", "))
+	}
+	if strings.TrimSpace(value) == "" {
+		delete(state.Config.Match, resource)
+		return nil
+	}
+	modes, err := todi.ParseMatchModes(value)
+	if err != nil {
+		return err
+	}
+	if state.Config.Match == nil {
+		state.Config.Match = map[string]string{}
+	}
+	state.Config.Match[resource] = joinMatchModes(modes)
+	return nil
+}
+
+// isAccountKey reports whether key is kept per profile.
+func isAccountKey(key string) bool {
+	switch key {
+	case "token", "api_base", "default_project", "default_labels":
+		return true
+	default:
+		return false
+	}
+}
+
+func setConfigKey(state *state, key, value string) int {
+	if isAccountKey(key) && state.Account == nil {
+		return reportError(state, errUnknownProfile(state.Profile))
 	}
 	switch key {
 	case "api_base":
-		state.Config.APIBase = value
+		state.Account.APIBase = value
 	case "default_project":
-		state.Config.Project = value
+		state.Account.Project = value
 	case "default_labels":
-		state.Config.Labels = value
+		state.Account.Labels = value
 	case "label_cli":
 		parsed, err := parseBool(value)
 		if err != nil {
-			if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-				return 2
-			}
-			return 2
+			return reportError(state, usageError{err})
 		}
 		state.Config.LabelCLI = parsed
-	default:
-		if _, err := fmt.Fprintln(state.Err, "error: unknown key:", key); err != nil {
-			return 2
+	case "max_retries":
+		parsed, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || parsed < 0 {
+			return reportError(state, usageErrorf("max_retries must be a non-negative integer"))
 		}
-		return 2
-	}
-	if err := state.Config.Save(state.ConfigPath); err != nil {
-		if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-			return 1
+		state.Config.MaxRetries = &parsed
+	case "retry_max_wait":
+		if _, err := parseRetryWait(value); err != nil {
+			return reportError(state, usageError{err})
 		}
-		return 1
-	}
-	if _, err := fmt.Fprintln(state.Out, "saved"); err != nil {
-		return 1
+		state.Config.RetryMaxWait = value
+	case "cache_ttl":
+		if _, err := parseDuration(value); err != nil {
+			return reportError(state, usageErrorf("invalid cache_ttl: %s", value))
+		}
+		state.Config.CacheTTL = value
+	case "color":
+		if _, err := parseColorMode(value); err != nil {
+			return reportError(state, usageError{err})
+		}
+		state.Config.Color = strings.ToLower(strings.TrimSpace(value))
+	case "credential_store":
+		value = strings.ToLower(strings.TrimSpace(value))
+		if !config.ValidStore(value) {
+			return reportError(state, usageErrorf("invalid credential_store: %s (use auto, secret-service, pass, file or plaintext)", value))
+		}
+		state.Config.CredentialStore = value
+	case "auth_base":
+		state.Config.AuthBase = value
+	case "oauth_client_id":
+		state.Config.OAuthClientID = value
+	case "oauth_client_secret":
+		state.Config.OAuthClientSecret = value
+	case "oauth_scopes":
+		state.Config.OAuthScopes = strings.ReplaceAll(value, " ", "")
+	default:
+		return reportError(state, usageErrorf("unknown key: %s", key))
 	}
 	return 0
 }
 
-func runConfigView(state *state) int {
+func runConfigView(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi config view", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var effective bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&effective, "effective", false, "Show resolved values and their sources")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printConfigUsage(state.Out)
+		return 0
+	}
+	if effective {
+		settings, err := state.effectiveSettings()
+		if err != nil {
+			return reportError(state, err)
+		}
+		if err := printSettings(state.Out, settings, state.Mode); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
 	data, err := os.ReadFile(state.ConfigPath)
 	if err != nil {
-		if _, writeErr := fmt.Fprintln(state.Err, "error:", err); writeErr != nil {
-			return 1
-		}
-		return 1
+		return reportError(state, err)
 	}
 	if _, err := fmt.Fprintln(state.Out, string(data)); err != nil {
 		return 1
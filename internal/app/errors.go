@@ -0,0 +1,110 @@
+package app
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+// Exit codes returned by Run. They are part of the CLI contract; see README.
+const (
+	exitError       = 1
+	exitUsage       = 2
+	exitAuth        = 3
+	exitNotFound    = 4
+	exitAmbiguous   = 5
+	exitRateLimited = 6
+	exitValidation  = 7
+)
+
+var errMissingToken = errors.New("missing Todoist token: run 'todi auth login' or set TODOIST_TOKEN")
+
+func errUnknownProfile(name string) error {
+	return usageErrorf("unknown profile: %s (run 'todi auth login --profile %s' or see 'todi profile list')", name, name)
+}
+
+// usageError marks an invalid invocation so reportError exits with exitUsage.
+type usageError struct {
+	err error
+}
+
+func (e usageError) Error() string {
+	return e.err.Error()
+}
+
+func (e usageError) Unwrap() error {
+	return e.err
+}
+
+func usageErrorf(format string, args ...any) error {
+	return usageError{err: fmt.Errorf(format, args...)}
+}
+
+// errorReport is the JSON shape written to stderr in --json mode.
+type errorReport struct {
+	Kind      string `json:"kind"`
+	Message   string `json:"message"`
+	ExitCode  int    `json:"exit_code"`
+	Status    int    `json:"status,omitempty"`
+	ErrorCode int    `json:"error_code,omitempty"`
+	ErrorTag  string `json:"error_tag,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Retryable bool   `json:"retryable"`
+	// Candidates lists the matches of an ambiguous name lookup.
+	Candidates []todi.Candidate `json:"candidates,omitempty"`
+}
+
+// reportError writes err to stderr in the active output mode and returns its exit code.
+func reportError(state *state, err error) int {
+	report := classifyError(err)
+	if state.Mode == modeJSON && !state.Rendered && printJSON(state.Err, map[string]any{"error": report}) == nil {
+		return report.ExitCode
+	}
+	writeLine(state.Err, "error:", err)
+	return report.ExitCode
+}
+
+func classifyError(err error) errorReport {
+	report := errorReport{Kind: "error", Message: err.Error(), ExitCode: exitError}
+	var usage usageError
+	var apiErr *todi.APIError
+	switch {
+	case errors.As(err, &usage):
+		report.Kind, report.ExitCode = "usage", exitUsage
+	case errors.Is(err, errMissingToken):
+		report.Kind, report.ExitCode = "auth", exitAuth
+	case errors.Is(err, todi.ErrAmbiguous):
+		report.Kind, report.ExitCode = "ambiguous", exitAmbiguous
+		var lookupErr *todi.LookupError
+		if errors.As(err, &lookupErr) {
+			report.Candidates = lookupErr.Candidates
+		}
+	case errors.As(err, &apiErr):
+		report.Kind, report.ExitCode = apiErrorKind(apiErr.StatusCode)
+		report.Status = apiErr.StatusCode
+		report.ErrorCode = apiErr.ErrorCode
+		report.ErrorTag = apiErr.ErrorTag
+		report.RequestID = apiErr.RequestID
+		report.Retryable = apiErr.Retryable
+	case errors.Is(err, todi.ErrNotFound):
+		report.Kind, report.ExitCode = "not_found", exitNotFound
+	}
+	return report
+}
+
+func apiErrorKind(status int) (string, int) {
+	switch {
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return "auth", exitAuth
+	case status == http.StatusNotFound:
+		return "not_found", exitNotFound
+	case status == http.StatusTooManyRequests:
+		return "rate_limited", exitRateLimited
+	case status == http.StatusBadRequest || status == http.StatusUnprocessableEntity:
+		return "validation", exitValidation
+	default:
+		return "api", exitError
+	}
+}
@@ -0,0 +1,384 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+func runFilter(ctx context.Context, state *state, args []string) int {
+	if len(args) == 0 {
+		printFilterUsage(state.Out)
+		return 2
+	}
+	switch args[0] {
+	case "list":
+		return runFilterList(ctx, state, args[1:])
+	case "get":
+		return runFilterGet(ctx, state, args[1:])
+	case "add":
+		return runFilterAdd(ctx, state, args[1:])
+	case "update":
+		return runFilterUpdate(ctx, state, args[1:])
+	case "delete":
+		return runFilterDelete(ctx, state, args[1:])
+	case "run":
+		return runFilterRun(ctx, state, args[1:])
+	case "-h", "--help", "help":
+		printFilterUsage(state.Out)
+		return 0
+	default:
+		code := reportError(state, usageErrorf("unknown filter command: %s", args[0]))
+		printFilterUsage(state.Err)
+		return code
+	}
+}
+
+func runFilterList(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi filter list", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var favorite bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&favorite, "favorite", false, "Only favorite filters")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printFilterUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	filters, err := client.ListFilters(ctx)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if favorite {
+		favorites := make([]todi.Filter, 0, len(filters))
+		for _, filter := range filters {
+			if filter.IsFavorite {
+				favorites = append(favorites, filter)
+			}
+		}
+		filters = favorites
+	}
+	if err := printFilters(state.Out, filters, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runFilterGet(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi filter get", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var forceID bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&forceID, "id", false, "Treat argument as filter ID")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printFilterUsage(state.Out)
+		return 0
+	}
+	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if identifier == "" {
+		return reportError(state, usageErrorf("filter identifier required"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	filter, err := resolveFilter(ctx, client, identifier, forceID)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := printFilter(state.Out, filter, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runFilterAdd(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi filter add", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var query string
+	var color string
+	var favorite bool
+	var order int
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.StringVar(&query, "query", "", "Filter query")
+	fs.StringVar(&color, "color", "", "Filter color")
+	fs.BoolVar(&favorite, "favorite", false, "Favorite filter")
+	fs.IntVar(&order, "order", 0, "Position in the filter list")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printFilterUsage(state.Out)
+		return 0
+	}
+	name := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if name == "" {
+		return reportError(state, usageErrorf("filter name required"))
+	}
+	if query == "" {
+		return reportError(state, usageErrorf("--query required"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	body := map[string]any{"name": name, "query": query}
+	if color != "" {
+		body["color"] = color
+	}
+	if favorite {
+		body["is_favorite"] = true
+	}
+	if order != 0 {
+		body["item_order"] = order
+	}
+
+	id, err := client.AddFilter(ctx, body)
+	if err != nil {
+		return reportError(state, err)
+	}
+	filter := todi.Filter{ID: id, Name: name, Query: query, Color: color, ItemOrder: order, IsFavorite: favorite}
+	if err := printFilter(state.Out, filter, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runFilterUpdate(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi filter update", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var forceID bool
+	var name string
+	var query string
+	var color string
+	var favorite bool
+	var unfavorite bool
+	var order int
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&forceID, "id", false, "Treat argument as filter ID")
+	fs.StringVar(&name, "name", "", "Filter name")
+	fs.StringVar(&query, "query", "", "Filter query")
+	fs.StringVar(&color, "color", "", "Filter color")
+	fs.BoolVar(&favorite, "favorite", false, "Favorite filter")
+	fs.BoolVar(&unfavorite, "unfavorite", false, "Remove favorite")
+	fs.IntVar(&order, "order", 0, "Position in the filter list")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printFilterUsage(state.Out)
+		return 0
+	}
+	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if identifier == "" {
+		return reportError(state, usageErrorf("filter identifier required"))
+	}
+	if favorite && unfavorite {
+		return reportError(state, usageErrorf("cannot use --favorite and --unfavorite together"))
+	}
+
+	body := map[string]any{}
+	if name != "" {
+		body["name"] = name
+	}
+	if query != "" {
+		body["query"] = query
+	}
+	if color != "" {
+		body["color"] = color
+	}
+	if favorite {
+		body["is_favorite"] = true
+	}
+	if unfavorite {
+		body["is_favorite"] = false
+	}
+	if order != 0 {
+		body["item_order"] = order
+	}
+	if len(body) == 0 {
+		return reportError(state, usageErrorf("no updates specified"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	filter, err := resolveFilter(ctx, client, identifier, forceID)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := client.UpdateFilter(ctx, filter.ID, body); err != nil {
+		return reportError(state, err)
+	}
+	updated, err := client.GetFilter(ctx, filter.ID)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := printFilter(state.Out, updated, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runFilterDelete(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi filter delete", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var forceID bool
+	var force bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&forceID, "id", false, "Treat argument as filter ID")
+	fs.BoolVar(&force, "force", false, "Skip confirmation")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printFilterUsage(state.Out)
+		return 0
+	}
+	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if identifier == "" {
+		return reportError(state, usageErrorf("filter identifier required"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	filter, err := resolveFilter(ctx, client, identifier, forceID)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := confirmDelete(state, "filter", identifier, force); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if err := client.DeleteFilter(ctx, filter.ID); err != nil {
+		return reportError(state, err)
+	}
+	if state.Mode == modeJSON {
+		if err := printJSON(state.Out, map[string]any{"id": filter.ID, "deleted": true}); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+	if _, err := fmt.Fprintf(state.Out, "deleted %s\n", filter.ID); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func runFilterRun(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi filter run", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var forceID bool
+	var lang string
+	var limit int
+	var cursor string
+	var all bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&forceID, "id", false, "Treat argument as filter ID")
+	fs.StringVar(&lang, "lang", "", "Filter query language code")
+	fs.IntVar(&limit, "limit", 50, "Max tasks per page (1-200)")
+	fs.StringVar(&cursor, "cursor", "", "Pagination cursor")
+	fs.BoolVar(&all, "all", false, "Fetch all pages")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printFilterUsage(state.Out)
+		return 0
+	}
+	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if identifier == "" {
+		return reportError(state, usageErrorf("filter identifier required"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	filter, err := resolveFilter(ctx, client, identifier, forceID)
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	params := map[string]string{"query": filter.Query, "lang": lang}
+	if limit > 0 {
+		params["limit"] = strconv.Itoa(limit)
+	}
+	if cursor != "" {
+		params["cursor"] = cursor
+	}
+
+	if all {
+		tasks, err := client.FilterTasksAll(ctx, params)
+		if err != nil {
+			return reportError(state, err)
+		}
+		if err := printTasks(state.Out, tasks, state.Mode); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+
+	tasks, next, err := client.FilterTasks(ctx, params)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if state.Mode == modeJSON {
+		payload := map[string]any{"results": withTaskURLs(tasks), "next_cursor": next}
+		if err := printJSON(state.Out, payload); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+	if err := printTasks(state.Out, tasks, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func resolveFilter(ctx context.Context, client *todi.Client, identifier string, forceID bool) (todi.Filter, error) {
+	if forceID {
+		return client.GetFilter(ctx, identifier)
+	}
+	return client.FindFilterByName(ctx, identifier)
+}
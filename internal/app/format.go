@@ -0,0 +1,264 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattjefferson/todi/internal/config"
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+// formatEscapes lets shell users write tabs and newlines in --format.
+var formatEscapes = strings.NewReplacer(`\t`, "\t", `\n`, "\n")
+
+var formatFuncs = template.FuncMap{
+	"due":      formatDue,
+	"join":     formatJoin,
+	"priority": formatPriority,
+	"json":     formatJSON,
+	"truncate": formatTruncate,
+	"date":     formatDate,
+}
+
+// parseFormat compiles a --format value. "@name" refers to a template saved
+// under formats in config.
+func parseFormat(value string, cfg *config.Config) (*template.Template, error) {
+	text := value
+	if name, ok := strings.CutPrefix(value, "@"); ok {
+		saved, found := cfg.Formats[strings.ToLower(name)]
+		if !found {
+			return nil, fmt.Errorf("unknown format: %s", name)
+		}
+		text = saved
+	}
+	text = unescapeFormat(text)
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+	tmpl, err := template.New("format").Funcs(formatFuncs).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid format: %w", err)
+	}
+	return tmpl, nil
+}
+
+// unescapeFormat expands formatEscapes in the literal text of a template,
+// leaving actions alone so their quoted strings keep their own escapes.
+func unescapeFormat(text string) string {
+	var b strings.Builder
+	for text != "" {
+		start := strings.Index(text, "{{")
+		if start < 0 {
+			b.WriteString(formatEscapes.Replace(text))
+			break
+		}
+		b.WriteString(formatEscapes.Replace(text[:start]))
+		end := strings.Index(text[start:], "}}")
+		if end < 0 {
+			b.WriteString(text[start:])
+			break
+		}
+		end += start + len("}}")
+		b.WriteString(text[start:end])
+		text = text[end:]
+	}
+	return b.String()
+}
+
+// templateRenderer executes a --format template once per record.
+type templateRenderer struct {
+	tmpl *template.Template
+}
+
+func (t templateRenderer) Render(out io.Writer, doc any) error {
+	fields := templateFields(t.tmpl.Tree.Root)
+	for _, record := range documentRecords(doc) {
+		data := plainJSON(record)
+		if values, ok := data.(map[string]any); ok {
+			fillTemplateFields(values, fields)
+		}
+		var rendered bytes.Buffer
+		if err := t.tmpl.Execute(&rendered, data); err != nil {
+			return err
+		}
+		if _, err := out.Write(rendered.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// templateFields returns the field chains, such as [due date] for
+// .due.date, that a template prints or tests from the record itself.
+// Fields read inside range and with blocks are relative to another value
+// and skipped.
+func templateFields(node parse.Node) [][]string {
+	var fields [][]string
+	var walk func(node parse.Node, top bool)
+	walk = func(node parse.Node, top bool) {
+		switch n := node.(type) {
+		case *parse.ListNode:
+			if n == nil {
+				return
+			}
+			for _, child := range n.Nodes {
+				walk(child, top)
+			}
+		case *parse.ActionNode:
+			walk(n.Pipe, top)
+		case *parse.TemplateNode:
+			walk(n.Pipe, top)
+		case *parse.IfNode:
+			walk(n.Pipe, top)
+			walk(n.List, top)
+			walk(n.ElseList, top)
+		case *parse.RangeNode:
+			// Ranging over a missing value prints nothing already.
+			walk(n.Pipe, false)
+			walk(n.List, false)
+			walk(n.ElseList, top)
+		case *parse.WithNode:
+			walk(n.Pipe, top)
+			walk(n.List, false)
+			walk(n.ElseList, top)
+		case *parse.PipeNode:
+			if n == nil {
+				return
+			}
+			for _, cmd := range n.Cmds {
+				walk(cmd, top)
+			}
+		case *parse.CommandNode:
+			for _, arg := range n.Args {
+				walk(arg, top)
+			}
+		case *parse.FieldNode:
+			if top {
+				fields = append(fields, n.Ident)
+			}
+		case *parse.VariableNode:
+			if len(n.Ident) > 1 && n.Ident[0] == "$" {
+				fields = append(fields, n.Ident[1:])
+			}
+		}
+	}
+	walk(node, true)
+	return fields
+}
+
+// fillTemplateFields sets the fields a template reads that are missing or
+// null in record to empty values, which print as nothing and test false,
+// where text/template would print "<no value>".
+func fillTemplateFields(record map[string]any, fields [][]string) {
+	for _, chain := range fields {
+		values := record
+		for i, key := range chain {
+			if values[key] == nil {
+				if i == len(chain)-1 {
+					values[key] = ""
+				} else {
+					values[key] = map[string]any{}
+				}
+			}
+			next, ok := values[key].(map[string]any)
+			if !ok {
+				break
+			}
+			values = next
+		}
+	}
+}
+
+func (templateRenderer) Flush(io.Writer) error { return nil }
+
+// formatDue accepts a task or its due object.
+func formatDue(value any) string {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return ""
+	}
+	if due, ok := obj["due"]; ok {
+		if obj, ok = due.(map[string]any); !ok {
+			return ""
+		}
+	}
+	for _, key := range []string{"date", "datetime", "string"} {
+		if s, ok := obj[key].(string); ok && s != "" {
+			return s
+		}
+	}
+	return ""
+}
+
+func formatJoin(sep string, value any) string {
+	items, ok := value.([]any)
+	if !ok {
+		if value == nil {
+			return ""
+		}
+		return fmt.Sprint(value)
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		parts = append(parts, fmt.Sprint(item))
+	}
+	return strings.Join(parts, sep)
+}
+
+// formatPriority renders an API priority as the apps do (4 is p1).
+func formatPriority(value any) string {
+	n, err := strconv.Atoi(fmt.Sprint(value))
+	if err != nil {
+		return ""
+	}
+	return todi.Task{Priority: n}.PriorityLabel()
+}
+
+func formatJSON(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func formatTruncate(n int, value any) string {
+	s := ""
+	if value != nil {
+		s = fmt.Sprint(value)
+	}
+	if n <= 0 || utf8.RuneCountInString(s) <= n {
+		return s
+	}
+	runes := []rune(s)
+	if n == 1 {
+		return string(runes[:1])
+	}
+	return string(runes[:n-1]) + "…"
+}
+
+// formatDate reformats an API date or timestamp with a Go time layout.
+// Timestamps are shown in local time.
+func formatDate(layout string, value any) (string, error) {
+	s, _ := value.(string)
+	if s == "" {
+		return "", nil
+	}
+	if t, err := time.Parse(time.RFC3339Nano, s); err == nil {
+		return t.Local().Format(layout), nil
+	}
+	for _, src := range []string{agendaFloatingLayout, agendaDateLayout} {
+		if t, err := time.ParseInLocation(src, s, time.Local); err == nil {
+			return t.Format(layout), nil
+		}
+	}
+	return "", fmt.Errorf("date: cannot parse %q", s)
+}
@@ -31,9 +31,9 @@ func runLabel(ctx context.Context, state *state, args []string) int {
 		printLabelUsage(state.Out)
 		return 0
 	default:
-		writeLine(state.Err, "error: unknown label command:", args[0])
+		code := reportError(state, usageErrorf("unknown label command: %s", args[0]))
 		printLabelUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -44,28 +44,30 @@ func runLabelList(ctx context.Context, state *state, args []string) int {
 	var limit int
 	var cursor string
 	var all bool
+	var view listView
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.IntVar(&limit, "limit", 50, "Max labels per page (1-200)")
 	fs.StringVar(&cursor, "cursor", "", "Pagination cursor")
 	fs.BoolVar(&all, "all", false, "Fetch all pages")
+	view.register(fs)
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printLabelUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) > 0 {
-		writeLine(state.Err, "error: unexpected arguments")
-		return 2
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if _, _, err := view.parse(labelView); err != nil {
+		return reportError(state, usageError{err})
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	params := map[string]string{}
@@ -79,32 +81,38 @@ func runLabelList(ctx context.Context, state *state, args []string) int {
 	if all {
 		labels, err := client.ListLabelsAll(ctx, params)
 		if err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
-		if err := printLabels(state.Out, labels, state.Mode); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+		if view.active() {
+			err = view.print(ctx, state, client, labelView, labels, nil)
+		} else {
+			err = printLabels(state.Out, labels, state.Mode)
+		}
+		if err != nil {
+			return reportError(state, err)
 		}
 		return 0
 	}
 
 	labels, next, err := client.ListLabels(ctx, params)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+	if view.active() {
+		if err := view.print(ctx, state, client, labelView, labels, &next); err != nil {
+			return reportError(state, err)
+		}
+		return 0
 	}
 	if state.Mode == modeJSON {
 		payload := map[string]any{"results": labels, "next_cursor": next}
 		if err := printJSON(state.Out, payload); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printLabels(state.Out, labels, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -118,8 +126,7 @@ func runLabelGet(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.BoolVar(&forceID, "id", false, "Treat argument as label ID")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printLabelUsage(state.Out)
@@ -127,24 +134,20 @@ func runLabelGet(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: label identifier required")
-		return 2
+		return reportError(state, usageErrorf("label identifier required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	label, err := resolveLabel(ctx, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := printLabel(state.Out, label, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -160,8 +163,7 @@ func runLabelAdd(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&color, "color", "", "Label color")
 	fs.BoolVar(&favorite, "favorite", false, "Favorite label")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printLabelUsage(state.Out)
@@ -169,14 +171,12 @@ func runLabelAdd(ctx context.Context, state *state, args []string) int {
 	}
 	name := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if name == "" {
-		writeLine(state.Err, "error: label name required")
-		return 2
+		return reportError(state, usageErrorf("label name required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	body := map[string]any{"name": name}
@@ -189,19 +189,16 @@ func runLabelAdd(ctx context.Context, state *state, args []string) int {
 
 	label, raw, err := client.CreateLabel(ctx, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printLabel(state.Out, label, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -223,8 +220,7 @@ func runLabelUpdate(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&favorite, "favorite", false, "Favorite label")
 	fs.BoolVar(&unfavorite, "unfavorite", false, "Remove favorite")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printLabelUsage(state.Out)
@@ -232,12 +228,10 @@ func runLabelUpdate(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: label identifier required")
-		return 2
+		return reportError(state, usageErrorf("label identifier required"))
 	}
 	if favorite && unfavorite {
-		writeLine(state.Err, "error: cannot use --favorite and --unfavorite together")
-		return 2
+		return reportError(state, usageErrorf("cannot use --favorite and --unfavorite together"))
 	}
 
 	body := map[string]any{}
@@ -254,31 +248,26 @@ func runLabelUpdate(ctx context.Context, state *state, args []string) int {
 		body["is_favorite"] = false
 	}
 	if len(body) == 0 {
-		writeLine(state.Err, "error: no updates specified")
-		return 2
+		return reportError(state, usageErrorf("no updates specified"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	labelID, err := resolveLabelIDFromIdentifier(ctx, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	label, raw, err := client.UpdateLabel(ctx, labelID, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -287,8 +276,7 @@ func runLabelUpdate(ctx context.Context, state *state, args []string) int {
 		return 0
 	}
 	if err := printLabel(state.Out, label, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -304,8 +292,7 @@ func runLabelDelete(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&forceID, "id", false, "Treat argument as label ID")
 	fs.BoolVar(&force, "force", false, "Skip confirmation")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printLabelUsage(state.Out)
@@ -313,35 +300,29 @@ func runLabelDelete(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: label identifier required")
-		return 2
+		return reportError(state, usageErrorf("label identifier required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	labelID, err := resolveLabelIDFromIdentifier(ctx, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := confirmDelete(state, "label", identifier, force); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 
 	raw, err := client.DeleteLabel(ctx, labelID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
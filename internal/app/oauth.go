@@ -0,0 +1,172 @@
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+const (
+	envAuthBase          = "TODOIST_AUTH_BASE"
+	envOAuthClientID     = "TODI_OAUTH_CLIENT_ID"
+	envOAuthClientSecret = "TODI_OAUTH_CLIENT_SECRET"
+	oauthCallbackPath    = "/callback"
+)
+
+// oauthFlags are the auth login flags of the OAuth flow. Empty values fall
+// back to the environment, then the config.
+type oauthFlags struct {
+	AuthBase     string
+	ClientID     string
+	ClientSecret string
+	Scopes       string
+	Port         int
+	NoBrowser    bool
+	Timeout      time.Duration
+}
+
+// oauthLogin runs the authorization code flow: it serves the redirect on a
+// loopback port, sends the user to the authorize page and exchanges the
+// returned code for a token.
+func oauthLogin(ctx context.Context, state *state, flags oauthFlags) (todi.OAuthToken, error) {
+	cfg := state.Config
+	authBase := firstNonEmpty(flags.AuthBase, os.Getenv(envAuthBase), cfg.AuthBase, todi.DefaultAuthBase)
+	oauth := todi.OAuthConfig{
+		ClientID:     firstNonEmpty(flags.ClientID, os.Getenv(envOAuthClientID), cfg.OAuthClientID),
+		ClientSecret: firstNonEmpty(flags.ClientSecret, os.Getenv(envOAuthClientSecret), cfg.OAuthClientSecret),
+		Scopes:       firstNonEmpty(flags.Scopes, cfg.OAuthScopes, todi.DefaultOAuthScopes),
+	}
+	if oauth.ClientID == "" || oauth.ClientSecret == "" {
+		return todi.OAuthToken{}, usageErrorf("oauth needs a client id and secret (--client-id/--client-secret, %s/%s or oauth_client_id/oauth_client_secret)", envOAuthClientID, envOAuthClientSecret)
+	}
+	if flags.Port < 0 || flags.Port > 65535 {
+		return todi.OAuthToken{}, usageErrorf("invalid --port: %d", flags.Port)
+	}
+
+	listener, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(flags.Port)))
+	if err != nil {
+		return todi.OAuthToken{}, fmt.Errorf("oauth callback: %w", err)
+	}
+	oauth.RedirectURL = "http://" + listener.Addr().String() + oauthCallbackPath
+	nonce, err := oauthState()
+	if err != nil {
+		_ = listener.Close()
+		return todi.OAuthToken{}, err
+	}
+	authorizeURL, err := todi.AuthorizeURL(authBase, oauth, nonce)
+	if err != nil {
+		_ = listener.Close()
+		return todi.OAuthToken{}, err
+	}
+
+	writeLine(state.Err, "Open this URL to authorize todi:")
+	writeLine(state.Err, "  "+authorizeURL)
+	if !flags.NoBrowser {
+		if err := openBrowser(authorizeURL); err != nil && state.Verbose {
+			writeLine(state.Err, "open browser: "+err.Error())
+		}
+	}
+	writeLine(state.Err, "Waiting for the redirect to "+oauth.RedirectURL+" ...")
+
+	ctx, cancel := context.WithTimeout(ctx, flags.Timeout)
+	defer cancel()
+	code, err := waitForCode(ctx, listener, nonce)
+	if err != nil {
+		return todi.OAuthToken{}, err
+	}
+	client := todi.NewClient(authBase, "", state.Verbose)
+	client.Retry = state.Retry
+	return client.ExchangeOAuthCode(ctx, oauth, code)
+}
+
+// callbackResult is what the redirect handler hands back to oauthLogin.
+type callbackResult struct {
+	code string
+	err  error
+}
+
+// waitForCode serves the OAuth redirect on listener until a request with the
+// expected state arrives, ctx ends, or the user denies access.
+func waitForCode(ctx context.Context, listener net.Listener, want string) (string, error) {
+	results := make(chan callbackResult, 1)
+	mux := http.NewServeMux()
+	mux.HandleFunc(oauthCallbackPath, func(w http.ResponseWriter, r *http.Request) {
+		q := r.URL.Query()
+		if q.Get("state") != want {
+			http.Error(w, "todi: state mismatch, start the login again", http.StatusBadRequest)
+			return
+		}
+		result := callbackResult{code: q.Get("code")}
+		switch {
+		case q.Get("error") != "":
+			result.err = fmt.Errorf("authorization denied: %s", q.Get("error"))
+			http.Error(w, "todi: authorization denied, you can close this window", http.StatusForbidden)
+		case result.code == "":
+			result.err = errors.New("oauth redirect has no code")
+			http.Error(w, "todi: missing code", http.StatusBadRequest)
+		default:
+			writeLine(w, "todi: authorized, you can close this window.")
+		}
+		select {
+		case results <- result:
+		default:
+		}
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	defer func() {
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+	select {
+	case result := <-results:
+		return result.code, result.err
+	case <-ctx.Done():
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return "", errors.New("timed out waiting for the oauth redirect")
+		}
+		return "", ctx.Err()
+	}
+}
+
+func oauthState() (string, error) {
+	buf := make([]byte, 16)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// openBrowser starts the platform's URL handler without waiting for it.
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	if err := cmd.Start(); err != nil {
+		return err
+	}
+	go func() {
+		_ = cmd.Wait()
+	}()
+	return nil
+}
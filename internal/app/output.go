@@ -4,9 +4,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
-	"text/tabwriter"
+	"time"
 
+	"github.com/mattjefferson/todi/internal/cache"
+	"github.com/mattjefferson/todi/internal/journal"
 	"github.com/mattjefferson/todi/internal/todi"
 )
 
@@ -16,27 +21,64 @@ const (
 	modeHuman outputMode = iota
 	modeJSON
 	modePlain
+	// modeWide is human output with every column of task tables.
+	modeWide
 )
 
 func printTasks(out io.Writer, tasks []todi.Task, mode outputMode) error {
 	switch mode {
 	case modeJSON:
-		payload := map[string]any{"results": tasks}
+		setColumns(out, taskView.tagKeys())
+		payload := map[string]any{"results": withTaskURLs(tasks)}
 		return printJSON(out, payload)
 	case modePlain:
 		for _, task := range tasks {
-			if _, err := fmt.Fprintf(out, "%s\t%s\t%s\n", task.ID, task.Content, dueSummary(task)); err != nil {
+			if _, err := fmt.Fprintln(out, strings.Join(taskPlainFields(task), "\t")); err != nil {
 				return err
 			}
 		}
 		return nil
 	default:
-		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
-		if _, err := fmt.Fprintln(w, "ID\tCONTENT\tDUE"); err != nil {
+		// Completed listings get a trailing COMPLETED column.
+		completed := slices.ContainsFunc(tasks, func(t todi.Task) bool { return t.CompletedAt != "" })
+		wide := mode == modeWide
+		header := "ID\tCONTENT\tDUE\tPRI\tDEADLINE\tLABELS"
+		if wide {
+			header += "\tPROJECT\tSECTION\tPARENT\tORDER\tDURATION\tASSIGNEE\tADDED\tUPDATED"
+		}
+		if completed || wide {
+			header += "\tCOMPLETED"
+		}
+		st := styleOf(out)
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, header); err != nil {
 			return err
 		}
 		for _, task := range tasks {
-			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", task.ID, task.Content, dueSummary(task)); err != nil {
+			row := []string{
+				st.id(task.ID),
+				task.Content,
+				st.due(task),
+				st.priority(task.Priority),
+				deadlineSummary(task),
+				st.labelList(task.Labels, ","),
+			}
+			if wide {
+				row = append(row,
+					task.ProjectID,
+					task.SectionID,
+					task.ParentID,
+					strconv.Itoa(task.ChildOrder),
+					durationSummary(task),
+					task.AssigneeID,
+					task.AddedAt,
+					task.UpdatedAt,
+				)
+			}
+			if completed || wide {
+				row = append(row, task.CompletedAt)
+			}
+			if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
 				return err
 			}
 		}
@@ -44,9 +86,69 @@ func printTasks(out io.Writer, tasks []todi.Task, mode outputMode) error {
 	}
 }
 
+func printTaskTree(out io.Writer, groups []taskGroup, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		var results []map[string]any
+		for _, group := range groups {
+			tasks := make([]todi.Task, 0, len(group.Tasks))
+			for _, entry := range group.Tasks {
+				tasks = append(tasks, entry.Item)
+			}
+			nested, err := nestTree(withTaskURLs(tasks), func(t todi.Task) string { return t.ID }, func(t todi.Task) string { return t.ParentID })
+			if err != nil {
+				return err
+			}
+			results = append(results, nested...)
+		}
+		if results == nil {
+			results = []map[string]any{}
+		}
+		return printJSON(out, map[string]any{"results": results})
+	case modePlain:
+		for _, group := range groups {
+			for _, entry := range group.Tasks {
+				fields := append(taskPlainFields(entry.Item), strconv.Itoa(entry.Depth))
+				if _, err := fmt.Fprintln(out, strings.Join(fields, "\t")); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	default:
+		st := styleOf(out)
+		for i, group := range groups {
+			if i > 0 {
+				if _, err := fmt.Fprintln(out); err != nil {
+					return err
+				}
+			}
+			if _, err := fmt.Fprintln(out, st.heading(group.Title)); err != nil {
+				return err
+			}
+			w := newTable(out)
+			if _, err := fmt.Fprintln(w, "ID\tCONTENT\tDUE\tPRI\tDEADLINE\tLABELS"); err != nil {
+				return err
+			}
+			for _, entry := range group.Tasks {
+				task := entry.Item
+				content := strings.Repeat("  ", entry.Depth) + task.Content
+				if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", st.id(task.ID), content, st.due(task), st.priority(task.Priority), deadlineSummary(task), st.labelList(task.Labels, ",")); err != nil {
+					return err
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
 func printActivities(out io.Writer, activities []todi.Activity, mode outputMode) error {
 	switch mode {
 	case modeJSON:
+		setColumns(out, activityView.tagKeys())
 		payload := map[string]any{"results": activities}
 		return printJSON(out, payload)
 	case modePlain:
@@ -63,13 +165,14 @@ func printActivities(out io.Writer, activities []todi.Activity, mode outputMode)
 		}
 		return nil
 	default:
-		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		st := styleOf(out)
+		w := newTable(out)
 		if _, err := fmt.Fprintln(w, "ID\tEVENT\tOBJECT\tOBJECT_ID\tDATE"); err != nil {
 			return err
 		}
 		for _, activity := range activities {
 			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n",
-				activity.ID,
+				st.id(activity.ID),
 				activity.EventType,
 				activity.ObjectType,
 				activity.ObjectID,
@@ -85,22 +188,61 @@ func printActivities(out io.Writer, activities []todi.Activity, mode outputMode)
 func printProjects(out io.Writer, projects []todi.Project, mode outputMode) error {
 	switch mode {
 	case modeJSON:
+		setColumns(out, projectView.tagKeys())
 		payload := map[string]any{"results": projects}
 		return printJSON(out, payload)
 	case modePlain:
 		for _, project := range projects {
-			if _, err := fmt.Fprintf(out, "%s\t%s\n", project.ID, project.Name); err != nil {
+			if _, err := fmt.Fprintln(out, strings.Join(projectPlainFields(project), "\t")); err != nil {
 				return err
 			}
 		}
 		return nil
 	default:
-		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
-		if _, err := fmt.Fprintln(w, "ID\tNAME"); err != nil {
+		st := styleOf(out)
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "ID\tNAME\tCOLOR\tVIEW\tFLAGS"); err != nil {
 			return err
 		}
 		for _, project := range projects {
-			if _, err := fmt.Fprintf(w, "%s\t%s\n", project.ID, project.Name); err != nil {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", st.id(project.ID), st.color(project.Color, project.Name), project.Color, project.ViewStyle, projectFlags(project)); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+}
+
+func printProjectTree(out io.Writer, projects []todi.Project, mode outputMode) error {
+	projectID := func(p todi.Project) string { return p.ID }
+	parentID := func(p todi.Project) string { return p.ParentID }
+	sorted := slices.Clone(projects)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].ChildOrder < sorted[j].ChildOrder })
+	switch mode {
+	case modeJSON:
+		nested, err := nestTree(sorted, projectID, parentID)
+		if err != nil {
+			return err
+		}
+		return printJSON(out, map[string]any{"results": nested})
+	case modePlain:
+		for _, entry := range walkTree(sorted, projectID, parentID) {
+			fields := append(projectPlainFields(entry.Item), strconv.Itoa(entry.Depth))
+			if _, err := fmt.Fprintln(out, strings.Join(fields, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		st := styleOf(out)
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "ID\tNAME\tCOLOR\tVIEW\tFLAGS"); err != nil {
+			return err
+		}
+		for _, entry := range walkTree(sorted, projectID, parentID) {
+			project := entry.Item
+			name := strings.Repeat("  ", entry.Depth) + project.Name
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", st.id(project.ID), st.color(project.Color, name), project.Color, project.ViewStyle, projectFlags(project)); err != nil {
 				return err
 			}
 		}
@@ -124,6 +266,7 @@ func printUser(out io.Writer, user todi.User, mode outputMode) error {
 func printSections(out io.Writer, sections []todi.Section, mode outputMode) error {
 	switch mode {
 	case modeJSON:
+		setColumns(out, sectionView.tagKeys())
 		payload := map[string]any{"results": sections}
 		return printJSON(out, payload)
 	case modePlain:
@@ -134,12 +277,13 @@ func printSections(out io.Writer, sections []todi.Section, mode outputMode) erro
 		}
 		return nil
 	default:
-		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		st := styleOf(out)
+		w := newTable(out)
 		if _, err := fmt.Fprintln(w, "ID\tNAME\tPROJECT\tORDER"); err != nil {
 			return err
 		}
 		for _, section := range sections {
-			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", section.ID, section.Name, section.ProjectID, section.SectionOrder); err != nil {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\n", st.id(section.ID), section.Name, section.ProjectID, section.SectionOrder); err != nil {
 				return err
 			}
 		}
@@ -150,6 +294,7 @@ func printSections(out io.Writer, sections []todi.Section, mode outputMode) erro
 func printLabels(out io.Writer, labels []todi.Label, mode outputMode) error {
 	switch mode {
 	case modeJSON:
+		setColumns(out, labelView.tagKeys())
 		payload := map[string]any{"results": labels}
 		return printJSON(out, payload)
 	case modePlain:
@@ -160,12 +305,13 @@ func printLabels(out io.Writer, labels []todi.Label, mode outputMode) error {
 		}
 		return nil
 	default:
-		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		st := styleOf(out)
+		w := newTable(out)
 		if _, err := fmt.Fprintln(w, "ID\tNAME\tCOLOR\tORDER\tFAVORITE"); err != nil {
 			return err
 		}
 		for _, label := range labels {
-			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%t\n", label.ID, label.Name, label.Color, label.Order, label.IsFavorite); err != nil {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%t\n", st.id(label.ID), st.color(label.Color, label.Name), label.Color, label.Order, label.IsFavorite); err != nil {
 				return err
 			}
 		}
@@ -176,6 +322,7 @@ func printLabels(out io.Writer, labels []todi.Label, mode outputMode) error {
 func printComments(out io.Writer, comments []todi.Comment, mode outputMode) error {
 	switch mode {
 	case modeJSON:
+		setColumns(out, commentView.tagKeys())
 		payload := map[string]any{"results": comments}
 		return printJSON(out, payload)
 	case modePlain:
@@ -186,12 +333,13 @@ func printComments(out io.Writer, comments []todi.Comment, mode outputMode) erro
 		}
 		return nil
 	default:
-		w := tabwriter.NewWriter(out, 0, 4, 2, ' ', 0)
+		st := styleOf(out)
+		w := newTable(out)
 		if _, err := fmt.Fprintln(w, "ID\tCONTENT\tPOSTED"); err != nil {
 			return err
 		}
 		for _, comment := range comments {
-			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", comment.ID, comment.Content, comment.PostedAt); err != nil {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", st.id(comment.ID), comment.Content, comment.PostedAt); err != nil {
 				return err
 			}
 		}
@@ -202,13 +350,45 @@ func printComments(out io.Writer, comments []todi.Comment, mode outputMode) erro
 func printTask(out io.Writer, task todi.Task, mode outputMode) error {
 	switch mode {
 	case modeJSON:
+		task.URL = task.WebURL()
 		return printJSON(out, task)
 	case modePlain:
-		_, err := fmt.Fprintf(out, "%s\t%s\t%s\n", task.ID, task.Content, dueSummary(task))
+		_, err := fmt.Fprintln(out, strings.Join(taskPlainFields(task), "\t"))
 		return err
 	default:
-		_, err := fmt.Fprintf(out, "ID: %s\nContent: %s\nDue: %s\n", task.ID, task.Content, dueSummary(task))
-		return err
+		st := styleOf(out)
+		if _, err := fmt.Fprintf(out, "ID: %s\nContent: %s\nDue: %s\n", st.id(task.ID), task.Content, st.due(task)); err != nil {
+			return err
+		}
+		details := [][2]string{
+			{"Description", task.Description},
+			{"Priority", st.priority(task.Priority)},
+			{"Recurring", yesIf(task.IsRecurring())},
+			{"Deadline", deadlineSummary(task)},
+			{"Duration", durationSummary(task)},
+			{"Labels", st.labelList(task.Labels, ", ")},
+			{"Project", task.ProjectID},
+			{"Section", task.SectionID},
+			{"Parent", task.ParentID},
+			{"Order", strconv.Itoa(task.ChildOrder)},
+			{"Assignee", task.AssigneeID},
+			{"Assigner", task.AssignerID},
+			{"Comments", strconv.Itoa(task.CommentCount)},
+			{"Completed", yesIf(task.IsCompleted)},
+			{"Added", task.AddedAt},
+			{"Updated", task.UpdatedAt},
+			{"Completed At", task.CompletedAt},
+			{"URL", task.WebURL()},
+		}
+		for _, detail := range details {
+			if detail[1] == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(out, "%s: %s\n", detail[0], detail[1]); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 }
 
@@ -217,11 +397,32 @@ func printProject(out io.Writer, project todi.Project, mode outputMode) error {
 	case modeJSON:
 		return printJSON(out, project)
 	case modePlain:
-		_, err := fmt.Fprintf(out, "%s\t%s\n", project.ID, project.Name)
+		_, err := fmt.Fprintln(out, strings.Join(projectPlainFields(project), "\t"))
 		return err
 	default:
-		_, err := fmt.Fprintf(out, "ID: %s\nName: %s\n", project.ID, project.Name)
-		return err
+		if _, err := fmt.Fprintf(out, "ID: %s\nName: %s\n", project.ID, project.Name); err != nil {
+			return err
+		}
+		details := [][2]string{
+			{"Description", project.Description},
+			{"Parent", project.ParentID},
+			{"Order", strconv.Itoa(project.ChildOrder)},
+			{"Color", project.Color},
+			{"View", project.ViewStyle},
+			{"Flags", projectFlags(project)},
+			{"Role", project.Role},
+			{"Created", project.CreatedAt},
+			{"Updated", project.UpdatedAt},
+		}
+		for _, detail := range details {
+			if detail[1] == "" {
+				continue
+			}
+			if _, err := fmt.Fprintf(out, "%s: %s\n", detail[0], detail[1]); err != nil {
+				return err
+			}
+		}
+		return nil
 	}
 }
 
@@ -277,6 +478,394 @@ func printComment(out io.Writer, comment todi.Comment, mode outputMode) error {
 	}
 }
 
+type cacheStatus struct {
+	Root     string          `json:"root"`
+	Enabled  bool            `json:"enabled"`
+	TTL      string          `json:"ttl"`
+	Accounts []cache.Account `json:"accounts"`
+}
+
+func printCacheStatus(out io.Writer, status cacheStatus, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, status)
+	case modePlain:
+		for _, account := range status.Accounts {
+			for _, entry := range account.Entries {
+				if _, err := fmt.Fprintf(out, "%s\t%s\t%s\t%d\t%t\n", account.Dir, entry.Key, entry.FetchedAt.Format(time.RFC3339), entry.Size, entry.Fresh); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	default:
+		if _, err := fmt.Fprintf(out, "Root: %s\nEnabled: %t\nTTL: %s\n", status.Root, status.Enabled, status.TTL); err != nil {
+			return err
+		}
+		for _, account := range status.Accounts {
+			if _, err := fmt.Fprintf(out, "\nDir: %s\nAPI Base: %s\n", account.Dir, account.APIBase); err != nil {
+				return err
+			}
+			if len(account.Entries) == 0 {
+				if _, err := fmt.Fprintln(out, "(empty)"); err != nil {
+					return err
+				}
+				continue
+			}
+			w := newTable(out)
+			if _, err := fmt.Fprintln(w, "KEY\tAGE\tSIZE\tSTATUS"); err != nil {
+				return err
+			}
+			for _, entry := range account.Entries {
+				freshness := "stale"
+				if entry.Fresh {
+					freshness = "fresh"
+				}
+				age := time.Since(entry.FetchedAt).Round(time.Second)
+				if _, err := fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", entry.Key, age, entry.Size, freshness); err != nil {
+					return err
+				}
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+func printReminders(out io.Writer, reminders []todi.Reminder, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		payload := map[string]any{"results": reminders}
+		return printJSON(out, payload)
+	case modePlain:
+		for _, reminder := range reminders {
+			if _, err := fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", reminder.ID, reminder.ItemID, reminder.Type, reminderSummary(reminder)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		st := styleOf(out)
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "ID\tTASK\tTYPE\tWHEN"); err != nil {
+			return err
+		}
+		for _, reminder := range reminders {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", st.id(reminder.ID), st.id(reminder.ItemID), reminder.Type, reminderSummary(reminder)); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+}
+
+func printReminder(out io.Writer, reminder todi.Reminder, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, reminder)
+	case modePlain:
+		_, err := fmt.Fprintf(out, "%s\t%s\t%s\t%s\n", reminder.ID, reminder.ItemID, reminder.Type, reminderSummary(reminder))
+		return err
+	default:
+		_, err := fmt.Fprintf(out, "ID: %s\nTask: %s\nType: %s\nWhen: %s\n", reminder.ID, reminder.ItemID, reminder.Type, reminderSummary(reminder))
+		return err
+	}
+}
+
+func printBulkResults(out, errOut io.Writer, past string, results []bulkResult, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, map[string]any{"results": results})
+	case modePlain:
+		// One row per input: input, id, ok or error, message.
+		for _, result := range results {
+			status := "ok"
+			if !result.OK {
+				status = "error"
+			}
+			row := []string{result.Input, result.ID, status, result.Error}
+			for i, cell := range row {
+				row[i] = tsvEscapes.Replace(cell)
+			}
+			if _, err := fmt.Fprintln(out, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	failed := 0
+	for _, result := range results {
+		if !result.OK {
+			failed++
+			writeLine(errOut, "error:", result.Input+":", result.Error)
+			continue
+		}
+		if _, err := fmt.Fprintf(out, "%s %s\n", past, result.ID); err != nil {
+			return err
+		}
+	}
+	writeLine(errOut, fmt.Sprintf("%d %s, %d failed", len(results)-failed, past, failed))
+	return nil
+}
+
+func printFilters(out io.Writer, filters []todi.Filter, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		payload := map[string]any{"results": filters}
+		return printJSON(out, payload)
+	case modePlain:
+		for _, filter := range filters {
+			if _, err := fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%d\t%t\n", filter.ID, filter.Name, filter.Query, filter.Color, filter.ItemOrder, filter.IsFavorite); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		st := styleOf(out)
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "ID\tNAME\tQUERY\tCOLOR\tFAVORITE"); err != nil {
+			return err
+		}
+		for _, filter := range filters {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%t\n", st.id(filter.ID), st.color(filter.Color, filter.Name), filter.Query, filter.Color, filter.IsFavorite); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+}
+
+func printFilter(out io.Writer, filter todi.Filter, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, filter)
+	case modePlain:
+		_, err := fmt.Fprintf(out, "%s\t%s\t%s\t%s\t%d\t%t\n", filter.ID, filter.Name, filter.Query, filter.Color, filter.ItemOrder, filter.IsFavorite)
+		return err
+	default:
+		_, err := fmt.Fprintf(out, "ID: %s\nName: %s\nQuery: %s\nColor: %s\nOrder: %d\nFavorite: %t\n", filter.ID, filter.Name, filter.Query, filter.Color, filter.ItemOrder, filter.IsFavorite)
+		return err
+	}
+}
+
+func printProfiles(out io.Writer, profiles []profileInfo, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, map[string]any{"results": profiles})
+	case modePlain:
+		for _, p := range profiles {
+			if _, err := fmt.Fprintf(out, "%s\t%t\t%t\t%s\t%s\t%s\n", p.Name, p.Current, p.HasToken, p.APIBase, p.DefaultProject, p.DefaultLabels); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "CURRENT\tNAME\tTOKEN\tAPI_BASE\tPROJECT\tLABELS"); err != nil {
+			return err
+		}
+		for _, p := range profiles {
+			marker := ""
+			if p.Current {
+				marker = "*"
+			}
+			token := "missing"
+			if p.HasToken {
+				token = "set"
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", marker, p.Name, token, p.APIBase, p.DefaultProject, p.DefaultLabels); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+}
+
+func printAuthStatus(out io.Writer, status authStatus, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, status)
+	case modePlain:
+		var userID, email string
+		if status.User != nil {
+			userID, email = status.User.ID, status.User.Email
+		}
+		_, err := fmt.Fprintf(out, "%s\t%s\t%t\t%s\t%s\n", status.Source, status.Store, status.Valid, userID, email)
+		return err
+	default:
+		source := status.Source
+		if status.Store != "" {
+			source += " (" + status.Store + ")"
+		}
+		if _, err := fmt.Fprintf(out, "Source: %s\n", source); err != nil {
+			return err
+		}
+		if status.Scopes != "" {
+			if _, err := fmt.Fprintf(out, "Scopes: %s\n", status.Scopes); err != nil {
+				return err
+			}
+		}
+		if status.User != nil {
+			if _, err := fmt.Fprintf(out, "User: %s (ID %s)\n", userLabel(*status.User), status.User.ID); err != nil {
+				return err
+			}
+		}
+		valid := "valid"
+		switch {
+		case status.Error == errTokenMissing:
+			valid = "missing"
+		case !status.Valid:
+			valid = "invalid: " + status.Error
+		}
+		_, err := fmt.Fprintf(out, "Token: %s\n", valid)
+		return err
+	}
+}
+
+func printMigrateResults(out io.Writer, results []migrateResult, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, map[string]any{"results": results})
+	case modePlain:
+		for _, r := range results {
+			if _, err := fmt.Fprintf(out, "%s\t%s\t%s\t%t\t%s\n", r.Profile, r.From, r.To, r.Migrated, r.Error); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if len(results) == 0 {
+			_, err := fmt.Fprintln(out, "no tokens to migrate")
+			return err
+		}
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "PROFILE\tFROM\tTO\tSTATUS"); err != nil {
+			return err
+		}
+		for _, r := range results {
+			status := "unchanged"
+			switch {
+			case r.Error != "":
+				status = "error: " + r.Error
+			case r.Migrated:
+				status = "migrated"
+			}
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", r.Profile, r.From, r.To, status); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+}
+
+func printSettings(out io.Writer, settings []setting, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, map[string]any{"results": settings})
+	case modePlain:
+		for _, s := range settings {
+			if _, err := fmt.Fprintf(out, "%s\t%s\t%s\n", s.Key, s.Value, s.Source); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "KEY\tVALUE\tSOURCE"); err != nil {
+			return err
+		}
+		for _, s := range settings {
+			if _, err := fmt.Fprintf(w, "%s\t%s\t%s\n", s.Key, s.Value, s.Source); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+}
+
+func printQueuedEntry(out io.Writer, entry journal.Entry, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, map[string]any{"queued": entry})
+	case modePlain:
+		_, err := fmt.Fprintf(out, "%d\t%s\t%s\n", entry.Seq, entry.Op, entry.ID)
+		return err
+	default:
+		_, err := fmt.Fprintf(out, "queued %s %s (#%d)\n", entry.Op, entry.ID, entry.Seq)
+		return err
+	}
+}
+
+func printJournalEntries(out io.Writer, entries []journal.Entry, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		payload := map[string]any{"results": entries}
+		return printJSON(out, payload)
+	case modePlain:
+		for _, entry := range entries {
+			if _, err := fmt.Fprintf(out, "%d\t%s\t%s\t%s\t%s\n", entry.Seq, entry.Op, entry.ID, entry.QueuedAt.Format(time.RFC3339), journalSummary(entry)); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "SEQ\tOP\tID\tQUEUED\tSUMMARY"); err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", entry.Seq, entry.Op, entry.ID, entry.QueuedAt.Local().Format(time.DateTime), journalSummary(entry)); err != nil {
+				return err
+			}
+		}
+		return w.Flush()
+	}
+}
+
+func printPushReport(out io.Writer, report journal.Report, mode outputMode) error {
+	switch mode {
+	case modeJSON:
+		return printJSON(out, report)
+	case modePlain:
+		for _, result := range report.Results {
+			if _, err := fmt.Fprintf(out, "%d\t%s\t%s\t%s\t%s\t%s\n", result.Entry.Seq, result.Entry.Op, result.Entry.ID, result.Status, result.ID, result.Error); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		if len(report.Results) == 0 {
+			_, err := fmt.Fprintln(out, "nothing to push")
+			return err
+		}
+		w := newTable(out)
+		if _, err := fmt.Fprintln(w, "SEQ\tOP\tID\tSTATUS\tDETAIL"); err != nil {
+			return err
+		}
+		for _, result := range report.Results {
+			detail := result.Error
+			if result.ID != "" {
+				detail = "created " + result.ID
+			}
+			if _, err := fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", result.Entry.Seq, result.Entry.Op, result.Entry.ID, result.Status, detail); err != nil {
+				return err
+			}
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintf(out, "applied %d, conflicts %d, rejected %d, pending %d\n",
+			report.Count(journal.StatusApplied),
+			report.Count(journal.StatusConflict),
+			report.Count(journal.StatusRejected),
+			report.Count(journal.StatusPending),
+		)
+		return err
+	}
+}
+
 func printJSON(out io.Writer, v any) error {
 	data, err := json.MarshalIndent(v, "", "  ")
 	if err != nil {
@@ -308,3 +897,136 @@ func dueSummary(task todi.Task) string {
 	}
 	return task.Due.String
 }
+
+func journalSummary(entry journal.Entry) string {
+	if content, ok := entry.Body["content"].(string); ok {
+		return content
+	}
+	keys := make([]string, 0, len(entry.Body))
+	for key := range entry.Body {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return strings.Join(keys, ",")
+}
+
+// taskPlainFields returns the --plain columns for a task. The first three
+// (id, content, due) are stable; new fields are only ever appended.
+func taskPlainFields(task todi.Task) []string {
+	return []string{
+		task.ID,
+		task.Content,
+		dueSummary(task),
+		strconv.Itoa(task.Priority),
+		task.ProjectID,
+		task.SectionID,
+		task.ParentID,
+		strconv.Itoa(task.ChildOrder),
+		strings.Join(task.Labels, ","),
+		deadlineSummary(task),
+		durationSummary(task),
+		task.AssigneeID,
+		task.AssignerID,
+		task.AddedAt,
+		task.UpdatedAt,
+		task.CompletedAt,
+		strconv.Itoa(task.CommentCount),
+		strconv.FormatBool(task.IsRecurring()),
+		task.WebURL(),
+	}
+}
+
+func withTaskURLs(tasks []todi.Task) []todi.Task {
+	if len(tasks) == 0 {
+		return tasks
+	}
+	out := make([]todi.Task, len(tasks))
+	for i, task := range tasks {
+		task.URL = task.WebURL()
+		out[i] = task
+	}
+	return out
+}
+
+func deadlineSummary(task todi.Task) string {
+	if task.Deadline == nil {
+		return ""
+	}
+	return task.Deadline.Date
+}
+
+func durationSummary(task todi.Task) string {
+	if task.Duration == nil || task.Duration.Amount == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d %s", task.Duration.Amount, task.Duration.Unit)
+}
+
+func yesIf(value bool) string {
+	if value {
+		return "yes"
+	}
+	return ""
+}
+
+// projectPlainFields returns the --plain columns for a project. The first
+// two (id, name) are stable; new fields are only ever appended.
+func projectPlainFields(project todi.Project) []string {
+	return []string{
+		project.ID,
+		project.Name,
+		project.ParentID,
+		strconv.Itoa(project.ChildOrder),
+		project.Color,
+		project.ViewStyle,
+		strconv.FormatBool(project.IsFavorite),
+		strconv.FormatBool(project.IsShared),
+		strconv.FormatBool(project.IsArchived),
+		strconv.FormatBool(project.IsInbox),
+	}
+}
+
+func projectFlags(project todi.Project) string {
+	var flags []string
+	if project.IsInbox {
+		flags = append(flags, "inbox")
+	}
+	if project.IsFavorite {
+		flags = append(flags, "favorite")
+	}
+	if project.IsShared {
+		flags = append(flags, "shared")
+	}
+	if project.IsArchived {
+		flags = append(flags, "archived")
+	}
+	return strings.Join(flags, ",")
+}
+
+func reminderSummary(reminder todi.Reminder) string {
+	switch reminder.Type {
+	case todi.ReminderRelative:
+		offset := 0
+		if reminder.MinuteOffset != nil {
+			offset = *reminder.MinuteOffset
+		}
+		if offset > 0 && offset%60 == 0 {
+			return fmt.Sprintf("%dh before due", offset/60)
+		}
+		return fmt.Sprintf("%dm before due", offset)
+	case todi.ReminderLocation:
+		summary := fmt.Sprintf("%s (%s,%s)", reminder.Name, reminder.LocLat, reminder.LocLong)
+		if reminder.LocTrigger == "on_leave" {
+			return "leaving " + summary
+		}
+		return "arriving " + summary
+	default:
+		if reminder.Due == nil {
+			return ""
+		}
+		if reminder.Due.Date != "" {
+			return reminder.Due.Date
+		}
+		return reminder.Due.String
+	}
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/mattjefferson/todi/internal/cache"
+	"github.com/mattjefferson/todi/internal/config"
+)
+
+// profileInfo is one row of todi profile list.
+type profileInfo struct {
+	Name           string `json:"name"`
+	Current        bool   `json:"current"`
+	HasToken       bool   `json:"has_token"`
+	APIBase        string `json:"api_base,omitempty"`
+	DefaultProject string `json:"default_project,omitempty"`
+	DefaultLabels  string `json:"default_labels,omitempty"`
+}
+
+func runProfile(_ context.Context, state *state, args []string) int {
+	if len(args) == 0 {
+		printProfileUsage(state.Out)
+		return 2
+	}
+	switch args[0] {
+	case "list":
+		return runProfileList(state, args[1:])
+	case "use":
+		return runProfileUse(state, args[1:])
+	case "remove":
+		return runProfileRemove(state, args[1:])
+	case "-h", "--help", "help":
+		printProfileUsage(state.Out)
+		return 0
+	default:
+		code := reportError(state, usageErrorf("unknown profile command: %s", args[0]))
+		printProfileUsage(state.Err)
+		return code
+	}
+}
+
+func runProfileList(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi profile list", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printProfileUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if err := printProfiles(state.Out, listProfiles(state), state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+// listProfiles returns the configured profiles by name. A config without
+// profiles lists its top-level account as the default profile.
+func listProfiles(state *state) []profileInfo {
+	cfg := state.Config
+	accounts := cfg.Profiles
+	if len(accounts) == 0 {
+		if cfg.Profile == (config.Profile{}) {
+			return []profileInfo{}
+		}
+		accounts = map[string]*config.Profile{config.DefaultProfile: &cfg.Profile}
+	}
+	current := state.Profile
+	if current == "" && len(cfg.Profiles) == 0 {
+		current = config.DefaultProfile
+	}
+	profiles := make([]profileInfo, 0, len(accounts))
+	for name, account := range accounts {
+		profiles = append(profiles, profileInfo{
+			Name:           name,
+			Current:        name == current,
+			HasToken:       account.TokenStore() != "",
+			APIBase:        account.APIBase,
+			DefaultProject: account.Project,
+			DefaultLabels:  account.Labels,
+		})
+	}
+	sort.Slice(profiles, func(i, j int) bool { return profiles[i].Name < profiles[j].Name })
+	return profiles
+}
+
+func runProfileUse(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi profile use", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printProfileUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) != 1 {
+		return reportError(state, usageErrorf("profile name required"))
+	}
+	name := fs.Args()[0]
+	if state.Config.Account(name) == nil {
+		return reportError(state, errUnknownProfile(name))
+	}
+	state.Config.Current = name
+	if err := state.Config.Save(state.ConfigPath); err != nil {
+		return reportError(state, err)
+	}
+	if state.Mode == modeJSON {
+		if err := printJSON(state.Out, map[string]any{"profile": name}); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+	if _, err := fmt.Fprintf(state.Out, "using profile %s\n", name); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func runProfileRemove(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi profile remove", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var force bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&force, "force", false, "Skip confirmation")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printProfileUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) != 1 {
+		return reportError(state, usageErrorf("profile name required"))
+	}
+	name := fs.Args()[0]
+	account := state.Config.Account(name)
+	if account == nil {
+		return reportError(state, errUnknownProfile(name))
+	}
+	removed := *account
+	if err := confirmDelete(state, "profile", name, force); err != nil {
+		return reportError(state, usageError{err})
+	}
+	// Read the token first: the cache is keyed by it.
+	token, _ := removed.ReadToken(state.configDir())
+	state.Config.RemoveProfile(name)
+	if err := state.Config.Save(state.ConfigPath); err != nil {
+		return reportError(state, err)
+	}
+	deleteOldToken(state, removed.TokenRef)
+	// The profile's cache is keyed by its account and would never be read again.
+	if token != "" {
+		if root, err := cache.DefaultRoot(); err == nil {
+			apiBase := firstNonEmpty(removed.APIBase, envOrDefault("TODOIST_API_BASE", defaultAPIBase))
+			_ = cache.Open(root, apiBase, token, state.CacheTTL).Clear()
+		}
+	}
+	if state.Mode == modeJSON {
+		if err := printJSON(state.Out, map[string]any{"profile": name, "removed": true}); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+	if _, err := fmt.Fprintf(state.Out, "removed profile %s\n", name); err != nil {
+		return 1
+	}
+	return 0
+}
@@ -35,9 +35,9 @@ func runProject(ctx context.Context, state *state, args []string) int {
 		printProjectUsage(state.Out)
 		return 0
 	default:
-		writeLine(state.Err, "error: unknown project command:", args[0])
+		code := reportError(state, usageErrorf("unknown project command: %s", args[0]))
 		printProjectUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -48,28 +48,39 @@ func runProjectList(ctx context.Context, state *state, args []string) int {
 	var limit int
 	var cursor string
 	var all bool
+	var tree bool
+	var filter projectFilter
+	var view listView
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.IntVar(&limit, "limit", 50, "Max projects per page (1-200)")
 	fs.StringVar(&cursor, "cursor", "", "Pagination cursor")
 	fs.BoolVar(&all, "all", false, "Fetch all pages")
+	fs.BoolVar(&tree, "tree", false, "Indent child projects under their parents")
+	fs.BoolVar(&filter.Favorite, "favorite", false, "Only favorite projects")
+	fs.BoolVar(&filter.Shared, "shared", false, "Only shared projects")
+	fs.BoolVar(&filter.Inbox, "inbox", false, "Only the Inbox project")
+	view.register(fs)
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printProjectUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) > 0 {
-		writeLine(state.Err, "error: unexpected arguments")
-		return 2
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if tree && view.active() {
+		return reportError(state, usageErrorf("cannot use --fields or --sort with --tree"))
+	}
+	if _, _, err := view.parse(projectView); err != nil {
+		return reportError(state, usageError{err})
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	params := map[string]string{}
@@ -80,35 +91,46 @@ func runProjectList(ctx context.Context, state *state, args []string) int {
 		params["cursor"] = cursor
 	}
 
-	if all {
+	if all || tree {
 		projects, err := client.ListProjectsAll(ctx)
 		if err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
-		if err := printProjects(state.Out, projects, state.Mode); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+		projects = filter.apply(projects)
+		switch {
+		case tree:
+			err = printProjectTree(state.Out, projects, state.Mode)
+		case view.active():
+			err = view.print(ctx, state, client, projectView, projects, nil)
+		default:
+			err = printProjects(state.Out, projects, state.Mode)
+		}
+		if err != nil {
+			return reportError(state, err)
 		}
 		return 0
 	}
 
 	projects, next, err := client.ListProjects(ctx, params)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+	projects = filter.apply(projects)
+	if view.active() {
+		if err := view.print(ctx, state, client, projectView, projects, &next); err != nil {
+			return reportError(state, err)
+		}
+		return 0
 	}
 	if state.Mode == modeJSON {
 		payload := map[string]any{"results": projects, "next_cursor": next}
 		if err := printJSON(state.Out, payload); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printProjects(state.Out, projects, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -122,8 +144,7 @@ func runProjectGet(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.BoolVar(&forceID, "id", false, "Treat argument as project ID")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printProjectUsage(state.Out)
@@ -131,24 +152,20 @@ func runProjectGet(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: project identifier required")
-		return 2
+		return reportError(state, usageErrorf("project identifier required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	project, err := resolveProject(ctx, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := printProject(state.Out, project, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -170,8 +187,7 @@ func runProjectAdd(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&favorite, "favorite", false, "Favorite project")
 	fs.StringVar(&viewStyle, "view", "", "View style (list|board)")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printProjectUsage(state.Out)
@@ -179,27 +195,23 @@ func runProjectAdd(ctx context.Context, state *state, args []string) int {
 	}
 	name := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if name == "" {
-		writeLine(state.Err, "error: project name required")
-		return 2
+		return reportError(state, usageErrorf("project name required"))
 	}
 	if viewStyle != "" {
 		viewStyle = strings.ToLower(viewStyle)
 	}
 	if viewStyle != "" && !validViewStyle(viewStyle) {
-		writeLine(state.Err, "error: view must be list or board")
-		return 2
+		return reportError(state, usageErrorf("view must be list or board"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	parentIDValue, err := resolveParentProjectID(ctx, client, parentName, parentID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	body := map[string]any{"name": name}
@@ -218,19 +230,16 @@ func runProjectAdd(ctx context.Context, state *state, args []string) int {
 
 	project, raw, err := client.CreateProject(ctx, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printProject(state.Out, project, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -254,8 +263,7 @@ func runProjectUpdate(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&unfavorite, "unfavorite", false, "Remove favorite")
 	fs.StringVar(&viewStyle, "view", "", "View style (list|board)")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printProjectUsage(state.Out)
@@ -263,19 +271,16 @@ func runProjectUpdate(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: project identifier required")
-		return 2
+		return reportError(state, usageErrorf("project identifier required"))
 	}
 	if viewStyle != "" {
 		viewStyle = strings.ToLower(viewStyle)
 	}
 	if favorite && unfavorite {
-		writeLine(state.Err, "error: cannot use --favorite and --unfavorite together")
-		return 2
+		return reportError(state, usageErrorf("cannot use --favorite and --unfavorite together"))
 	}
 	if viewStyle != "" && !validViewStyle(viewStyle) {
-		writeLine(state.Err, "error: view must be list or board")
-		return 2
+		return reportError(state, usageErrorf("view must be list or board"))
 	}
 
 	body := map[string]any{}
@@ -295,31 +300,26 @@ func runProjectUpdate(ctx context.Context, state *state, args []string) int {
 		body["view_style"] = viewStyle
 	}
 	if len(body) == 0 {
-		writeLine(state.Err, "error: no updates specified")
-		return 2
+		return reportError(state, usageErrorf("no updates specified"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectID, err := resolveProjectIDFromIdentifier(ctx, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	project, raw, err := client.UpdateProject(ctx, projectID, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -328,8 +328,7 @@ func runProjectUpdate(ctx context.Context, state *state, args []string) int {
 		return 0
 	}
 	if err := printProject(state.Out, project, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -345,8 +344,7 @@ func runProjectDelete(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&forceID, "id", false, "Treat argument as project ID")
 	fs.BoolVar(&force, "force", false, "Skip confirmation")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printProjectUsage(state.Out)
@@ -354,35 +352,29 @@ func runProjectDelete(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: project identifier required")
-		return 2
+		return reportError(state, usageErrorf("project identifier required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectID, err := resolveProjectIDFromIdentifier(ctx, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := confirmDelete(state, "project", identifier, force); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 
 	raw, err := client.DeleteProject(ctx, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -399,8 +391,7 @@ func runProjectArchive(ctx context.Context, state *state, args []string) int {
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -417,8 +408,7 @@ func runProjectUnarchive(ctx context.Context, state *state, args []string) int {
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -437,8 +427,7 @@ func projectAction(ctx context.Context, state *state, action string, args []stri
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.BoolVar(&forceID, "id", false, "Treat argument as project ID")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return "", nil, 2
+		return "", nil, reportError(state, usageError{err})
 	}
 	if help {
 		printProjectUsage(state.Out)
@@ -446,20 +435,17 @@ func projectAction(ctx context.Context, state *state, action string, args []stri
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: project identifier required")
-		return "", nil, 2
+		return "", nil, reportError(state, usageErrorf("project identifier required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return "", nil, 1
+		return "", nil, reportError(state, err)
 	}
 
 	projectID, err := resolveProjectIDFromIdentifier(ctx, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return "", nil, 1
+		return "", nil, reportError(state, err)
 	}
 
 	var raw []byte
@@ -472,8 +458,7 @@ func projectAction(ctx context.Context, state *state, action string, args []stri
 		return "", nil, 2
 	}
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return "", nil, 1
+		return "", nil, reportError(state, err)
 	}
 	return projectID, raw, 0
 }
@@ -494,7 +479,7 @@ func resolveProjectIDFromIdentifier(ctx context.Context, client *todi.Client, id
 
 func resolveParentProjectID(ctx context.Context, client *todi.Client, parentName, parentID string) (string, error) {
 	if parentName != "" && parentID != "" {
-		return "", fmt.Errorf("cannot use --parent and --parent-id together")
+		return "", usageErrorf("cannot use --parent and --parent-id together")
 	}
 	if parentID != "" {
 		return parentID, nil
@@ -513,3 +498,30 @@ func validViewStyle(value string) bool {
 		return false
 	}
 }
+
+// projectFilter keeps projects matching every enabled flag.
+type projectFilter struct {
+	Favorite bool
+	Shared   bool
+	Inbox    bool
+}
+
+func (f projectFilter) apply(projects []todi.Project) []todi.Project {
+	if !f.Favorite && !f.Shared && !f.Inbox {
+		return projects
+	}
+	filtered := make([]todi.Project, 0, len(projects))
+	for _, project := range projects {
+		if f.Favorite && !project.IsFavorite {
+			continue
+		}
+		if f.Shared && !project.IsShared {
+			continue
+		}
+		if f.Inbox && !project.IsInbox {
+			continue
+		}
+		filtered = append(filtered, project)
+	}
+	return filtered
+}
@@ -0,0 +1,411 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+func runReminder(ctx context.Context, state *state, args []string) int {
+	if len(args) == 0 {
+		printReminderUsage(state.Out)
+		return 2
+	}
+	switch args[0] {
+	case "list":
+		return runReminderList(ctx, state, args[1:])
+	case "add":
+		return runReminderAdd(ctx, state, args[1:])
+	case "update":
+		return runReminderUpdate(ctx, state, args[1:])
+	case "delete":
+		return runReminderDelete(ctx, state, args[1:])
+	case "-h", "--help", "help":
+		printReminderUsage(state.Out)
+		return 0
+	default:
+		code := reportError(state, usageErrorf("unknown reminder command: %s", args[0]))
+		printReminderUsage(state.Err)
+		return code
+	}
+}
+
+func runReminderList(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi reminder list", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var taskTitle string
+	var taskID string
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.StringVar(&taskTitle, "task", "", "Task title (exact match)")
+	fs.StringVar(&taskID, "task-id", "", "Task ID")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printReminderUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	var reminders []todi.Reminder
+	if taskTitle == "" && taskID == "" {
+		reminders, err = client.ListReminders(ctx)
+	} else {
+		taskID, err = resolveReminderTask(ctx, client, taskTitle, taskID)
+		if err != nil {
+			return reportError(state, err)
+		}
+		reminders, err = client.ListTaskReminders(ctx, taskID)
+	}
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := printReminders(state.Out, reminders, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runReminderAdd(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi reminder add", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var taskTitle string
+	var taskID string
+	var spec reminderSpec
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.StringVar(&taskTitle, "task", "", "Task title (exact match)")
+	fs.StringVar(&taskID, "task-id", "", "Task ID")
+	spec.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printReminderUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if taskTitle == "" && taskID == "" {
+		return reportError(state, usageErrorf("task required (--task or --task-id)"))
+	}
+	body, err := spec.args(nil)
+	if err != nil {
+		return reportError(state, usageError{err})
+	}
+	if _, ok := body["type"]; !ok {
+		return reportError(state, usageErrorf("use one of --before, --at, or --location"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	taskID, err = resolveReminderTask(ctx, client, taskTitle, taskID)
+	if err != nil {
+		return reportError(state, err)
+	}
+	body["item_id"] = taskID
+
+	id, err := client.AddReminder(ctx, body)
+	if err != nil {
+		return reportError(state, err)
+	}
+	reminder := spec.reminder()
+	reminder.ID = id
+	reminder.ItemID = taskID
+	if err := printReminder(state.Out, reminder, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runReminderUpdate(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi reminder update", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var spec reminderSpec
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	spec.register(fs)
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printReminderUsage(state.Out)
+		return 0
+	}
+	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if identifier == "" {
+		return reportError(state, usageErrorf("reminder ID required"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	current, err := client.FindReminder(ctx, identifier)
+	if err != nil {
+		return reportError(state, err)
+	}
+	body, err := spec.args(&current)
+	if err != nil {
+		return reportError(state, usageError{err})
+	}
+	if len(body) == 0 {
+		return reportError(state, usageErrorf("no updates specified"))
+	}
+	if err := client.UpdateReminder(ctx, identifier, body); err != nil {
+		return reportError(state, err)
+	}
+	reminder, err := client.FindReminder(ctx, identifier)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := printReminder(state.Out, reminder, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runReminderDelete(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi reminder delete", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var force bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&force, "force", false, "Skip confirmation")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printReminderUsage(state.Out)
+		return 0
+	}
+	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
+	if identifier == "" {
+		return reportError(state, usageErrorf("reminder ID required"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	if _, err := client.FindReminder(ctx, identifier); err != nil {
+		return reportError(state, err)
+	}
+	if err := confirmDelete(state, "reminder", identifier, force); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if err := client.DeleteReminder(ctx, identifier); err != nil {
+		return reportError(state, err)
+	}
+	if state.Mode == modeJSON {
+		if err := printJSON(state.Out, map[string]any{"id": identifier, "deleted": true}); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+	if _, err := fmt.Fprintf(state.Out, "deleted %s\n", identifier); err != nil {
+		return 1
+	}
+	return 0
+}
+
+func resolveReminderTask(ctx context.Context, client *todi.Client, taskTitle, taskID string) (string, error) {
+	if taskTitle != "" && taskID != "" {
+		return "", usageErrorf("cannot use --task and --task-id together")
+	}
+	if taskID != "" {
+		return taskID, nil
+	}
+	return resolveTaskID(ctx, client, taskTitle, false)
+}
+
+// reminderSpec holds the reminder flags shared by add and update.
+type reminderSpec struct {
+	Before    string
+	At        string
+	Location  string
+	Lat       string
+	Long      string
+	Trigger   string
+	Radius    int
+	NotifyUID string
+}
+
+func (s *reminderSpec) register(fs *flag.FlagSet) {
+	fs.StringVar(&s.Before, "before", "", "Relative: time before due (minutes or duration, e.g. 30 or 1h)")
+	fs.StringVar(&s.At, "at", "", "Absolute: datetime (RFC3339 or YYYY-MM-DDTHH:MM) or due string")
+	fs.StringVar(&s.Location, "location", "", "Location: place name")
+	fs.StringVar(&s.Lat, "lat", "", "Location latitude")
+	fs.StringVar(&s.Long, "long", "", "Location longitude")
+	fs.StringVar(&s.Trigger, "trigger", "", "Location trigger (enter|leave)")
+	fs.IntVar(&s.Radius, "radius", 0, "Location radius in meters")
+	fs.StringVar(&s.NotifyUID, "notify", "", "User ID to notify")
+}
+
+func (s *reminderSpec) isLocation() bool {
+	return s.Location != "" || s.Lat != "" || s.Long != "" || s.Trigger != "" || s.Radius != 0
+}
+
+// args validates the flags and returns Sync API reminder arguments. current
+// is the reminder being updated, or nil for a new one. A location reminder
+// can have its location fields changed one at a time; any other reminder
+// needs all of them to become one.
+func (s *reminderSpec) args(current *todi.Reminder) (map[string]any, error) {
+	kinds := 0
+	for _, set := range []bool{s.Before != "", s.At != "", s.isLocation()} {
+		if set {
+			kinds++
+		}
+	}
+	if kinds > 1 {
+		return nil, fmt.Errorf("use only one of --before, --at, or location flags")
+	}
+	body := map[string]any{}
+	if s.NotifyUID != "" {
+		body["notify_uid"] = s.NotifyUID
+	}
+	switch {
+	case s.Before != "":
+		minutes, err := parseMinutes(s.Before)
+		if err != nil {
+			return nil, err
+		}
+		body["type"] = todi.ReminderRelative
+		body["minute_offset"] = minutes
+	case s.At != "":
+		body["type"] = todi.ReminderAbsolute
+		body["due"] = reminderDue(s.At)
+	case s.isLocation():
+		if s.Radius < 0 {
+			return nil, fmt.Errorf("radius must be >= 0")
+		}
+		if current != nil && current.Type == todi.ReminderLocation {
+			return s.locationUpdate(body)
+		}
+		if s.Location == "" || s.Lat == "" || s.Long == "" {
+			return nil, fmt.Errorf("location reminders require --location, --lat and --long")
+		}
+		trigger, err := locationTrigger(s.Trigger)
+		if err != nil {
+			return nil, err
+		}
+		body["type"] = todi.ReminderLocation
+		body["name"] = s.Location
+		body["loc_lat"] = s.Lat
+		body["loc_long"] = s.Long
+		body["loc_trigger"] = trigger
+		if s.Radius > 0 {
+			body["radius"] = s.Radius
+		}
+	}
+	return body, nil
+}
+
+// locationUpdate adds the location flags that were given to body.
+func (s *reminderSpec) locationUpdate(body map[string]any) (map[string]any, error) {
+	for key, value := range map[string]string{"name": s.Location, "loc_lat": s.Lat, "loc_long": s.Long} {
+		if value != "" {
+			body[key] = value
+		}
+	}
+	if s.Trigger != "" {
+		trigger, err := locationTrigger(s.Trigger)
+		if err != nil {
+			return nil, err
+		}
+		body["loc_trigger"] = trigger
+	}
+	if s.Radius > 0 {
+		body["radius"] = s.Radius
+	}
+	return body, nil
+}
+
+// reminder returns the reminder described by the flags, for printing.
+func (s *reminderSpec) reminder() todi.Reminder {
+	body, err := s.args(nil)
+	if err != nil {
+		return todi.Reminder{}
+	}
+	reminder := todi.Reminder{NotifyUID: s.NotifyUID}
+	reminder.Type, _ = body["type"].(string)
+	if minutes, ok := body["minute_offset"].(int); ok {
+		reminder.MinuteOffset = &minutes
+	}
+	if due, ok := body["due"].(map[string]any); ok {
+		reminder.Due = &todi.Due{}
+		reminder.Due.Date, _ = due["date"].(string)
+		reminder.Due.String, _ = due["string"].(string)
+	}
+	reminder.Name, _ = body["name"].(string)
+	reminder.LocLat, _ = body["loc_lat"].(string)
+	reminder.LocLong, _ = body["loc_long"].(string)
+	reminder.LocTrigger, _ = body["loc_trigger"].(string)
+	reminder.Radius, _ = body["radius"].(int)
+	return reminder
+}
+
+// parseMinutes accepts bare minutes or a Go duration.
+func parseMinutes(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if minutes, err := strconv.Atoi(value); err == nil {
+		if minutes < 0 {
+			return 0, fmt.Errorf("--before must be >= 0")
+		}
+		return minutes, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid --before: %s", value)
+	}
+	return int(d.Minutes()), nil
+}
+
+// reminderDue builds an absolute reminder due: a datetime when value parses
+// as one, otherwise a natural-language due string.
+func reminderDue(value string) map[string]any {
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			if layout == time.RFC3339 {
+				return map[string]any{"date": t.UTC().Format("2006-01-02T15:04:05Z")}
+			}
+			return map[string]any{"date": t.Format("2006-01-02T15:04:05")}
+		}
+	}
+	return map[string]any{"string": value}
+}
+
+func locationTrigger(value string) (string, error) {
+	switch strings.ToLower(value) {
+	case "", "enter", "on_enter":
+		return "on_enter", nil
+	case "leave", "on_leave":
+		return "on_leave", nil
+	default:
+		return "", fmt.Errorf("trigger must be enter or leave")
+	}
+}
@@ -0,0 +1,446 @@
+package app
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Values for --output. csv, tsv, ndjson and yaml are rendered from the JSON
+// output of a command, so every command that supports --json supports them.
+const (
+	outputHuman  = "human"
+	outputWide   = "wide"
+	outputPlain  = "plain"
+	outputJSON   = "json"
+	outputCSV    = "csv"
+	outputTSV    = "tsv"
+	outputNDJSON = "ndjson"
+	outputYAML   = "yaml"
+)
+
+// jsonObject is a decoded JSON object that keeps its key order.
+type jsonObject struct {
+	Keys   []string
+	Values map[string]any
+}
+
+func (o *jsonObject) Get(key string) (any, bool) {
+	value, ok := o.Values[key]
+	return value, ok
+}
+
+func (o *jsonObject) Set(key string, value any) {
+	if _, ok := o.Values[key]; !ok {
+		o.Keys = append(o.Keys, key)
+	}
+	o.Values[key] = value
+}
+
+// MarshalJSON writes the keys in their original order.
+func (o *jsonObject) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, key := range o.Keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		name, err := json.Marshal(key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(o.Values[key])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// decodeOrdered reads one JSON value, decoding objects as *jsonObject and
+// numbers as json.Number.
+func decodeOrdered(dec *json.Decoder) (any, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			obj := &jsonObject{Values: map[string]any{}}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := keyTok.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid object key %v", keyTok)
+				}
+				value, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				obj.Set(key, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return obj, nil
+		case '[':
+			list := []any{}
+			for dec.More() {
+				value, err := decodeOrdered(dec)
+				if err != nil {
+					return nil, err
+				}
+				list = append(list, value)
+			}
+			if _, err := dec.Token(); err != nil {
+				return nil, err
+			}
+			return list, nil
+		}
+		return nil, fmt.Errorf("unexpected %v", t)
+	default:
+		return tok, nil
+	}
+}
+
+// plainJSON converts *jsonObject values to maps, for templates and cells.
+func plainJSON(value any) any {
+	switch v := value.(type) {
+	case *jsonObject:
+		out := make(map[string]any, len(v.Values))
+		for key, item := range v.Values {
+			out[key] = plainJSON(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = plainJSON(item)
+		}
+		return out
+	default:
+		return value
+	}
+}
+
+// documentRecords returns the items of a "results" list, or the document
+// itself when it is a single object.
+func documentRecords(doc any) []any {
+	switch v := doc.(type) {
+	case []any:
+		return v
+	case *jsonObject:
+		if results, ok := v.Values["results"].([]any); ok {
+			return results
+		}
+	}
+	return []any{doc}
+}
+
+// renderer turns JSON documents into another format.
+type renderer interface {
+	Render(out io.Writer, doc any) error
+	Flush(out io.Writer) error
+}
+
+// jsonOutput is the stdout of a command run in JSON mode whose output is
+// rendered differently. Each Write from printJSON holds whole documents,
+// which are rendered as they arrive so paged output streams. Writes that
+// are not JSON, such as help text, pass through.
+type jsonOutput struct {
+	out    io.Writer
+	render renderer
+}
+
+func (j *jsonOutput) Write(p []byte) (int, error) {
+	var docs []any
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	for {
+		doc, err := decodeOrdered(dec)
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return j.out.Write(p)
+		}
+		docs = append(docs, doc)
+	}
+	for _, doc := range docs {
+		if err := j.render.Render(j.out, doc); err != nil {
+			return 0, err
+		}
+	}
+	return len(p), nil
+}
+
+// SetColumns fixes the columns of table output before the first document
+// is rendered, so fields a resource omits when empty keep their column.
+func (j *jsonOutput) SetColumns(columns []string) {
+	if t, ok := j.render.(*tableRenderer); ok && !t.header {
+		t.columns = columns
+	}
+}
+
+// setColumns passes the full column list of a listing to out when it
+// renders tables.
+func setColumns(out io.Writer, columns []string) {
+	if j, ok := out.(*jsonOutput); ok {
+		j.SetColumns(columns)
+	}
+}
+
+func (j *jsonOutput) Flush() error {
+	return j.render.Flush(j.out)
+}
+
+func newRenderer(output string) renderer {
+	switch output {
+	case outputCSV:
+		return &tableRenderer{csv: true}
+	case outputTSV:
+		return &tableRenderer{}
+	case outputNDJSON:
+		return ndjsonRenderer{}
+	case outputYAML:
+		return &yamlRenderer{}
+	default:
+		return nil
+	}
+}
+
+// ndjsonRenderer writes one compact JSON object per record.
+type ndjsonRenderer struct{}
+
+func (ndjsonRenderer) Render(out io.Writer, doc any) error {
+	for _, record := range documentRecords(doc) {
+		data, err := json.Marshal(record)
+		if err != nil {
+			return err
+		}
+		if _, err := fmt.Fprintln(out, string(data)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ndjsonRenderer) Flush(io.Writer) error { return nil }
+
+// tableRenderer writes records as CSV or TSV with a header row. Columns
+// are the ones set by the command, or else the keys of the first
+// document's records, in order of appearance.
+type tableRenderer struct {
+	csv     bool
+	columns []string
+	header  bool
+	writer  *csv.Writer
+}
+
+func (t *tableRenderer) Render(out io.Writer, doc any) error {
+	records := documentRecords(doc)
+	if !t.header && t.columns == nil {
+		seen := map[string]bool{}
+		for _, record := range records {
+			obj, ok := record.(*jsonObject)
+			if !ok {
+				continue
+			}
+			for _, key := range obj.Keys {
+				if !seen[key] {
+					seen[key] = true
+					t.columns = append(t.columns, key)
+				}
+			}
+		}
+	}
+	if !t.header {
+		if len(t.columns) == 0 {
+			return nil
+		}
+		if err := t.writeRow(out, t.columns); err != nil {
+			return err
+		}
+		t.header = true
+	}
+	for _, record := range records {
+		values, _ := plainJSON(record).(map[string]any)
+		row := make([]string, 0, len(t.columns))
+		for _, column := range t.columns {
+			row = append(row, viewValue(values, column, false))
+		}
+		if err := t.writeRow(out, row); err != nil {
+			return err
+		}
+	}
+	return t.Flush(out)
+}
+
+var tsvEscapes = strings.NewReplacer("\t", " ", "\r\n", " ", "\n", " ", "\r", " ")
+
+func (t *tableRenderer) writeRow(out io.Writer, row []string) error {
+	if t.csv {
+		if t.writer == nil {
+			t.writer = csv.NewWriter(out)
+		}
+		return t.writer.Write(row)
+	}
+	cells := make([]string, len(row))
+	for i, cell := range row {
+		cells[i] = tsvEscapes.Replace(cell)
+	}
+	_, err := fmt.Fprintln(out, strings.Join(cells, "\t"))
+	return err
+}
+
+func (t *tableRenderer) Flush(io.Writer) error {
+	if t.writer == nil {
+		return nil
+	}
+	t.writer.Flush()
+	return t.writer.Error()
+}
+
+// yamlRenderer writes each document as YAML, separated by "---".
+type yamlRenderer struct {
+	docs int
+}
+
+func (y *yamlRenderer) Render(out io.Writer, doc any) error {
+	var buf bytes.Buffer
+	if y.docs > 0 {
+		buf.WriteString("---\n")
+	}
+	y.docs++
+	writeYAML(&buf, doc, 0)
+	_, err := out.Write(buf.Bytes())
+	return err
+}
+
+func (y *yamlRenderer) Flush(io.Writer) error { return nil }
+
+func writeYAML(buf *bytes.Buffer, value any, indent int) {
+	pad := strings.Repeat("  ", indent)
+	switch v := value.(type) {
+	case *jsonObject:
+		if len(v.Keys) == 0 {
+			buf.WriteString(pad + "{}\n")
+			return
+		}
+		for _, key := range v.Keys {
+			buf.WriteString(pad + yamlString(key) + ":")
+			writeYAMLChild(buf, v.Values[key], indent)
+		}
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(pad + "[]\n")
+			return
+		}
+		for _, item := range v {
+			writeYAMLItem(buf, item, indent)
+		}
+	default:
+		buf.WriteString(pad + yamlScalar(value) + "\n")
+	}
+}
+
+// writeYAMLItem writes a list item, starting a non-empty collection on the
+// "- " line.
+func writeYAMLItem(buf *bytes.Buffer, item any, indent int) {
+	pad := strings.Repeat("  ", indent)
+	var child bytes.Buffer
+	switch v := item.(type) {
+	case *jsonObject:
+		if len(v.Keys) > 0 {
+			writeYAML(&child, v, indent+1)
+		}
+	case []any:
+		if len(v) > 0 {
+			writeYAML(&child, v, indent+1)
+		}
+	}
+	if child.Len() == 0 {
+		buf.WriteString(pad + "-")
+		writeYAMLChild(buf, item, indent)
+		return
+	}
+	buf.WriteString(pad + "- ")
+	buf.Write(child.Bytes()[len(pad)+2:])
+}
+
+// writeYAMLChild writes a value after "key:" or "-": scalars and empty
+// collections inline, everything else on the following lines.
+func writeYAMLChild(buf *bytes.Buffer, value any, indent int) {
+	switch v := value.(type) {
+	case *jsonObject:
+		if len(v.Keys) == 0 {
+			buf.WriteString(" {}\n")
+			return
+		}
+	case []any:
+		if len(v) == 0 {
+			buf.WriteString(" []\n")
+			return
+		}
+	default:
+		buf.WriteString(" " + yamlScalar(value) + "\n")
+		return
+	}
+	buf.WriteString("\n")
+	writeYAML(buf, value, indent+1)
+}
+
+func yamlScalar(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(v)
+	case json.Number:
+		return v.String()
+	case string:
+		return yamlString(v)
+	default:
+		return yamlString(fmt.Sprint(v))
+	}
+}
+
+var yamlPlain = regexp.MustCompile(`^[A-Za-z_/][A-Za-z0-9 _./@()-]*$`)
+
+// yamlString leaves simple words unquoted and double-quotes anything YAML
+// could read as another type.
+func yamlString(s string) string {
+	switch strings.ToLower(s) {
+	case "true", "false", "yes", "no", "on", "off", "y", "n", "null", "~":
+		return quoteYAML(s)
+	}
+	if yamlPlain.MatchString(s) && !strings.HasSuffix(s, " ") {
+		return s
+	}
+	return quoteYAML(s)
+}
+
+func quoteYAML(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(s); err != nil {
+		return strconv.Quote(s)
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
@@ -31,9 +31,9 @@ func runSection(ctx context.Context, state *state, args []string) int {
 		printSectionUsage(state.Out)
 		return 0
 	default:
-		writeLine(state.Err, "error: unknown section command:", args[0])
+		code := reportError(state, usageErrorf("unknown section command: %s", args[0]))
 		printSectionUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -46,6 +46,7 @@ func runSectionList(ctx context.Context, state *state, args []string) int {
 	var limit int
 	var cursor string
 	var all bool
+	var view listView
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.StringVar(&projectName, "project", "", "Project title (exact match)")
@@ -53,29 +54,29 @@ func runSectionList(ctx context.Context, state *state, args []string) int {
 	fs.IntVar(&limit, "limit", 50, "Max sections per page (1-200)")
 	fs.StringVar(&cursor, "cursor", "", "Pagination cursor")
 	fs.BoolVar(&all, "all", false, "Fetch all pages")
+	view.register(fs)
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printSectionUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) > 0 {
-		writeLine(state.Err, "error: unexpected arguments")
-		return 2
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if _, _, err := view.parse(sectionView); err != nil {
+		return reportError(state, usageError{err})
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	params := map[string]string{}
@@ -92,32 +93,38 @@ func runSectionList(ctx context.Context, state *state, args []string) int {
 	if all {
 		sections, err := client.ListSectionsAll(ctx, params)
 		if err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
-		if err := printSections(state.Out, sections, state.Mode); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+		if view.active() {
+			err = view.print(ctx, state, client, sectionView, sections, nil)
+		} else {
+			err = printSections(state.Out, sections, state.Mode)
+		}
+		if err != nil {
+			return reportError(state, err)
 		}
 		return 0
 	}
 
 	sections, next, err := client.ListSections(ctx, params)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+	if view.active() {
+		if err := view.print(ctx, state, client, sectionView, sections, &next); err != nil {
+			return reportError(state, err)
+		}
+		return 0
 	}
 	if state.Mode == modeJSON {
 		payload := map[string]any{"results": sections, "next_cursor": next}
 		if err := printJSON(state.Out, payload); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printSections(state.Out, sections, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -135,8 +142,7 @@ func runSectionGet(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&projectName, "project", "", "Project title (exact match)")
 	fs.StringVar(&projectID, "project-id", "", "Project ID")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printSectionUsage(state.Out)
@@ -144,30 +150,25 @@ func runSectionGet(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: section identifier required")
-		return 2
+		return reportError(state, usageErrorf("section identifier required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	section, err := resolveSection(ctx, client, identifier, forceID, projectIDValue)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := printSection(state.Out, section, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -185,8 +186,7 @@ func runSectionAdd(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&projectID, "project-id", "", "Project ID")
 	fs.StringVar(&orderValue, "order", "", "Section order")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printSectionUsage(state.Out)
@@ -194,51 +194,43 @@ func runSectionAdd(ctx context.Context, state *state, args []string) int {
 	}
 	name := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if name == "" {
-		writeLine(state.Err, "error: section name required")
-		return 2
+		return reportError(state, usageErrorf("section name required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if projectIDValue == "" {
-		writeLine(state.Err, "error: project required")
-		return 2
+		return reportError(state, usageErrorf("project required"))
 	}
 
 	body := map[string]any{"name": name, "project_id": projectIDValue}
 	if orderValue != "" {
 		order, err := strconv.Atoi(orderValue)
 		if err != nil {
-			writeLine(state.Err, "error: order must be an integer")
-			return 2
+			return reportError(state, usageErrorf("order must be an integer"))
 		}
 		body["order"] = order
 	}
 
 	section, raw, err := client.CreateSection(ctx, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printSection(state.Out, section, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -258,8 +250,7 @@ func runSectionUpdate(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&projectID, "project-id", "", "Project ID")
 	fs.StringVar(&name, "name", "", "Section name")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printSectionUsage(state.Out)
@@ -267,42 +258,35 @@ func runSectionUpdate(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: section identifier required")
-		return 2
+		return reportError(state, usageErrorf("section identifier required"))
 	}
 	if name == "" {
-		writeLine(state.Err, "error: name required")
-		return 2
+		return reportError(state, usageErrorf("name required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	sectionID, err := resolveSectionIDFromIdentifier(ctx, client, identifier, forceID, projectIDValue)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	body := map[string]any{"name": name}
 	section, raw, err := client.UpdateSection(ctx, sectionID, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -311,8 +295,7 @@ func runSectionUpdate(ctx context.Context, state *state, args []string) int {
 		return 0
 	}
 	if err := printSection(state.Out, section, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -332,8 +315,7 @@ func runSectionDelete(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&projectID, "project-id", "", "Project ID")
 	fs.BoolVar(&force, "force", false, "Skip confirmation")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printSectionUsage(state.Out)
@@ -341,41 +323,34 @@ func runSectionDelete(ctx context.Context, state *state, args []string) int {
 	}
 	identifier := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if identifier == "" {
-		writeLine(state.Err, "error: section identifier required")
-		return 2
+		return reportError(state, usageErrorf("section identifier required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	sectionID, err := resolveSectionIDFromIdentifier(ctx, client, identifier, forceID, projectIDValue)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := confirmDelete(state, "section", identifier, force); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 
 	raw, err := client.DeleteSection(ctx, sectionID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -0,0 +1,186 @@
+package app
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/mattjefferson/todi/internal/config"
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+const (
+	envDefaultProject = "TODI_DEFAULT_PROJECT"
+	envDefaultLabels  = "TODI_DEFAULT_LABELS"
+)
+
+// setting is a resolved config value and where it came from: a flag, an
+// environment variable, a config file path, or "default".
+type setting struct {
+	Key    string `json:"key"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// configSources records which global settings were set by a flag, the
+// environment or a built-in default rather than the user config.
+func configSources(globals globalFlags, cfg *config.Config, account *config.Profile) map[string]string {
+	sources := map[string]string{}
+	switch {
+	case globals.Profile != "":
+		sources["profile"] = "flag --profile"
+	case os.Getenv("TODI_PROFILE") != "":
+		sources["profile"] = "env TODI_PROFILE"
+	case cfg.Current == "":
+		sources["profile"] = "default"
+	}
+	switch {
+	case globals.APIBase != "":
+		sources["api_base"] = "flag --api-base"
+	case account != nil && account.APIBase != "":
+	case os.Getenv("TODOIST_API_BASE") != "":
+		sources["api_base"] = "env TODOIST_API_BASE"
+	default:
+		sources["api_base"] = "default"
+	}
+	if globals.LabelCLI {
+		sources["label_cli"] = "flag --label-cli"
+	}
+	if globals.Retries >= 0 {
+		sources["max_retries"] = "flag --retries"
+	}
+	if globals.RetryWait != "" {
+		sources["retry_max_wait"] = "flag --retry-max-wait"
+	}
+	if globals.NoColor {
+		sources["color"] = "flag --no-color"
+	} else if os.Getenv("NO_COLOR") != "" {
+		sources["color"] = "env NO_COLOR"
+	}
+	return sources
+}
+
+// envSource is "env <key>" when the variable is set, else fallback.
+func envSource(key, fallback string) string {
+	if os.Getenv(key) != "" {
+		return "env " + key
+	}
+	return fallback
+}
+
+// taskDefaults are the default project and labels for new tasks.
+type taskDefaults struct {
+	Project setting
+	Labels  setting
+}
+
+// taskDefaults resolves default_project and default_labels from, in order,
+// TODI_DEFAULT_PROJECT/TODI_DEFAULT_LABELS, the nearest .todi.json and the
+// selected profile. Command flags take precedence over all of them.
+func (s *state) taskDefaults() (taskDefaults, error) {
+	dir, err := os.Getwd()
+	if err != nil {
+		return taskDefaults{}, err
+	}
+	local, err := config.FindLocal(dir)
+	if err != nil {
+		return taskDefaults{}, err
+	}
+	var layers []settingLayer
+	if local != nil {
+		layers = append(layers, settingLayer{&local.Profile, local.Filename})
+	}
+	if s.Account != nil {
+		layers = append(layers, settingLayer{s.Account, s.accountSource()})
+	}
+	return taskDefaults{
+		Project: layeredSetting("default_project", envDefaultProject, layers, func(p *config.Profile) string { return p.Project }),
+		Labels:  layeredSetting("default_labels", envDefaultLabels, layers, func(p *config.Profile) string { return p.Labels }),
+	}, nil
+}
+
+// settingLayer is one config file level of a layered setting.
+type settingLayer struct {
+	Profile *config.Profile
+	Source  string
+}
+
+func layeredSetting(key, env string, layers []settingLayer, get func(*config.Profile) string) setting {
+	if value := strings.TrimSpace(os.Getenv(env)); value != "" {
+		return setting{Key: key, Value: value, Source: "env " + env}
+	}
+	for _, layer := range layers {
+		if value := strings.TrimSpace(get(layer.Profile)); value != "" {
+			return setting{Key: key, Value: value, Source: layer.Source}
+		}
+	}
+	return setting{Key: key, Source: "default"}
+}
+
+// accountSource describes where the selected account's settings live.
+func (s *state) accountSource() string {
+	if s.Profile == "" {
+		return s.ConfigPath
+	}
+	return fmt.Sprintf("%s (profile %s)", s.ConfigPath, s.Profile)
+}
+
+// effectiveSettings lists the value in effect for every config key except
+// the secrets (token, oauth_client_secret), with its source.
+func (s *state) effectiveSettings() ([]setting, error) {
+	defaults, err := s.taskDefaults()
+	if err != nil {
+		return nil, err
+	}
+	cfg := s.Config
+	source := func(key string, set bool) string {
+		if src, ok := s.Sources[key]; ok {
+			return src
+		}
+		if set {
+			return s.ConfigPath
+		}
+		return "default"
+	}
+	color := firstNonEmpty(cfg.Color, "auto")
+	if _, ok := s.Sources["color"]; ok {
+		color = "never"
+	}
+	apiBase, ok := s.Sources["api_base"]
+	if !ok {
+		apiBase = s.accountSource()
+	}
+	settings := []setting{
+		{Key: "profile", Value: s.Profile, Source: source("profile", true)},
+		{Key: "api_base", Value: s.APIBase, Source: apiBase},
+		defaults.Project,
+		defaults.Labels,
+		{Key: "label_cli", Value: strconv.FormatBool(s.LabelCLI), Source: source("label_cli", cfg.LabelCLI)},
+		{Key: "max_retries", Value: strconv.Itoa(s.Retry.MaxRetries), Source: source("max_retries", cfg.MaxRetries != nil)},
+		{Key: "retry_max_wait", Value: s.Retry.MaxWait.String(), Source: source("retry_max_wait", cfg.RetryMaxWait != "")},
+		{Key: "cache_ttl", Value: s.CacheTTL.String(), Source: source("cache_ttl", cfg.CacheTTL != "")},
+		{Key: "color", Value: color, Source: source("color", cfg.Color != "")},
+		{Key: "auth_base", Value: firstNonEmpty(os.Getenv(envAuthBase), cfg.AuthBase, todi.DefaultAuthBase), Source: envSource(envAuthBase, source("auth_base", cfg.AuthBase != ""))},
+		{Key: "oauth_client_id", Value: firstNonEmpty(os.Getenv(envOAuthClientID), cfg.OAuthClientID), Source: envSource(envOAuthClientID, source("oauth_client_id", cfg.OAuthClientID != ""))},
+		{Key: "oauth_scopes", Value: firstNonEmpty(cfg.OAuthScopes, todi.DefaultOAuthScopes), Source: source("oauth_scopes", cfg.OAuthScopes != "")},
+		{Key: "credential_store", Value: config.ResolveStore(cfg.CredentialStore), Source: source("credential_store", cfg.CredentialStore != "")},
+	}
+	for _, resource := range todi.MatchResources {
+		modes := s.Match[resource]
+		if len(modes) == 0 {
+			modes = todi.DefaultMatchModes
+		}
+		key := "match." + resource
+		settings = append(settings, setting{Key: key, Value: joinMatchModes(modes), Source: source(key, cfg.Match[resource] != "")})
+	}
+	return settings, nil
+}
+
+func joinMatchModes(modes []todi.MatchMode) string {
+	names := make([]string, len(modes))
+	for i, mode := range modes {
+		names[i] = string(mode)
+	}
+	return strings.Join(names, ",")
+}
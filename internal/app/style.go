@@ -0,0 +1,277 @@
+package app
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/mattjefferson/todi/internal/config"
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+type colorMode int
+
+const (
+	colorAuto colorMode = iota
+	colorAlways
+	colorNever
+)
+
+func parseColorMode(value string) (colorMode, error) {
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "", "auto":
+		return colorAuto, nil
+	case "always":
+		return colorAlways, nil
+	case "never":
+		return colorNever, nil
+	default:
+		return colorAuto, fmt.Errorf("invalid color: %s (use auto, always or never)", value)
+	}
+}
+
+// useColor decides whether human output is styled. --no-color and NO_COLOR
+// always win; otherwise the color config key applies, and auto styles
+// terminals only.
+func useColor(noColor bool, cfg *config.Config, out *os.File) (bool, error) {
+	mode, err := parseColorMode(cfg.Color)
+	if err != nil {
+		return false, err
+	}
+	if noColor || os.Getenv("NO_COLOR") != "" {
+		return false, nil
+	}
+	switch mode {
+	case colorAlways:
+		return true, nil
+	case colorNever:
+		return false, nil
+	default:
+		return isTTY(out) && os.Getenv("TERM") != "dumb", nil
+	}
+}
+
+// colorOutput marks stdout as accepting ANSI styling. Printers pick it up
+// through styleOf.
+type colorOutput struct {
+	io.Writer
+	// labels returns label colors keyed by lowercase name. It is loaded on
+	// first use and may be empty.
+	labels func() map[string]string
+	// location returns the user's timezone, loaded on first use.
+	location func() *time.Location
+}
+
+// style applies ANSI styling to human output. The zero value leaves text
+// untouched.
+type style struct {
+	on       bool
+	labels   func() map[string]string
+	location func() *time.Location
+}
+
+func styleOf(out io.Writer) style {
+	color, ok := out.(*colorOutput)
+	if !ok {
+		return style{}
+	}
+	return style{on: true, labels: color.labels, location: color.location}
+}
+
+const (
+	ansiReset = "\x1b[0m"
+	ansiBold  = "1"
+	ansiDim   = "2"
+	ansiRed   = "31"
+)
+
+// priorityColors follows the app: red, orange and blue for p1 to p3; p4 is
+// left plain.
+var priorityColors = map[int]string{
+	4: "1;31",
+	3: "38;5;208",
+	2: "34",
+}
+
+// todoistColors maps the API color names to their hex values.
+var todoistColors = map[string]string{
+	"berry_red":   "b8256f",
+	"red":         "db4035",
+	"orange":      "ff9933",
+	"yellow":      "fad000",
+	"olive_green": "afb83b",
+	"lime_green":  "7ecc49",
+	"green":       "299438",
+	"mint_green":  "6accbc",
+	"teal":        "158fad",
+	"sky_blue":    "14aaf5",
+	"light_blue":  "96c3eb",
+	"blue":        "4073ff",
+	"grape":       "884dff",
+	"violet":      "af38eb",
+	"lavender":    "eb96eb",
+	"magenta":     "e05194",
+	"salmon":      "ff8d85",
+	"charcoal":    "808080",
+	"grey":        "b8b8b8",
+	"taupe":       "ccac93",
+}
+
+func (s style) paint(code, text string) string {
+	if !s.on || code == "" || text == "" {
+		return text
+	}
+	return "\x1b[" + code + "m" + text + ansiReset
+}
+
+func (s style) heading(text string) string {
+	return s.paint(ansiBold, text)
+}
+
+func (s style) id(id string) string {
+	return s.paint(ansiDim, id)
+}
+
+// priority renders an API priority (4 is p1) as its label.
+func (s style) priority(priority int) string {
+	return s.paint(priorityColors[priority], todi.Task{Priority: priority}.PriorityLabel())
+}
+
+// due renders the task's due date, in red once the day has passed.
+func (s style) due(task todi.Task) string {
+	text := dueSummary(task)
+	if s.on && s.overdue(task.Due) {
+		return s.paint(ansiRed, text)
+	}
+	return text
+}
+
+// overdue reports whether due is on a day before today in the user's
+// timezone.
+func (s style) overdue(due *todi.Due) bool {
+	loc := time.Local
+	if s.location != nil {
+		loc = s.location()
+	}
+	t, ok := dueTime(due, loc)
+	return ok && startOfDay(t).Before(startOfDay(time.Now().In(loc)))
+}
+
+// color paints text in a Todoist color name.
+func (s style) color(name, text string) string {
+	hex, ok := todoistColors[name]
+	if !ok {
+		return text
+	}
+	r, _ := strconv.ParseUint(hex[0:2], 16, 8)
+	g, _ := strconv.ParseUint(hex[2:4], 16, 8)
+	b, _ := strconv.ParseUint(hex[4:6], 16, 8)
+	return s.paint(fmt.Sprintf("38;2;%d;%d;%d", r, g, b), text)
+}
+
+// labelList joins label names, each painted in the label's color.
+func (s style) labelList(names []string, sep string) string {
+	if !s.on || s.labels == nil {
+		return strings.Join(names, sep)
+	}
+	colors := s.labels()
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, s.color(colors[strings.ToLower(name)], name))
+	}
+	return strings.Join(parts, sep)
+}
+
+// table lays out tab-separated rows like text/tabwriter with the padding
+// used across todi, but measures cells without ANSI escapes so styled
+// columns stay aligned.
+type table struct {
+	out io.Writer
+	buf bytes.Buffer
+}
+
+const tablePadding = 2
+
+func newTable(out io.Writer) *table {
+	return &table{out: out}
+}
+
+func (t *table) Write(p []byte) (int, error) {
+	return t.buf.Write(p)
+}
+
+// Flush writes the buffered rows. As with tabwriter, a column's width is set
+// by the contiguous run of lines that have a tab-terminated cell in it.
+func (t *table) Flush() error {
+	if t.buf.Len() == 0 {
+		return nil
+	}
+	lines := strings.Split(t.buf.String(), "\n")
+	t.buf.Reset()
+	rows := make([][]string, len(lines))
+	columns := 0
+	for i, line := range lines {
+		rows[i] = strings.Split(line, "\t")
+		columns = max(columns, len(rows[i])-1)
+	}
+	widths := make([][]int, len(rows))
+	for i, row := range rows {
+		widths[i] = make([]int, len(row)-1)
+	}
+	for col := 0; col < columns; col++ {
+		for start := 0; start < len(rows); {
+			if len(rows[start])-1 <= col {
+				start++
+				continue
+			}
+			end, width := start, 0
+			for ; end < len(rows) && len(rows[end])-1 > col; end++ {
+				width = max(width, visibleWidth(rows[end][col])+tablePadding)
+			}
+			for i := start; i < end; i++ {
+				widths[i][col] = width
+			}
+			start = end
+		}
+	}
+	var b strings.Builder
+	for i, row := range rows {
+		for col, cell := range row[:len(row)-1] {
+			b.WriteString(cell)
+			b.WriteString(strings.Repeat(" ", widths[i][col]-visibleWidth(cell)))
+		}
+		b.WriteString(row[len(row)-1])
+		if i < len(rows)-1 {
+			b.WriteByte('\n')
+		}
+	}
+	_, err := io.WriteString(t.out, b.String())
+	return err
+}
+
+// visibleWidth counts the runes of s outside ANSI escape sequences.
+func visibleWidth(s string) int {
+	if !strings.Contains(s, "\x1b[") {
+		return utf8.RuneCountInString(s)
+	}
+	width := 0
+	for i := 0; i < len(s); {
+		if strings.HasPrefix(s[i:], "\x1b[") {
+			j := i + 2
+			for j < len(s) && (s[j] < 0x40 || s[j] > 0x7e) {
+				j++
+			}
+			i = j + 1
+			continue
+		}
+		_, size := utf8.DecodeRuneInString(s[i:])
+		width++
+		i += size
+	}
+	return width
+}
@@ -0,0 +1,185 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/mattjefferson/todi/internal/journal"
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+func runSync(ctx context.Context, state *state, args []string) int {
+	if len(args) == 0 {
+		printSyncUsage(state.Out)
+		return 2
+	}
+	switch args[0] {
+	case "push":
+		return runSyncPush(ctx, state, args[1:])
+	case "status":
+		return runSyncStatus(state, args[1:])
+	case "clear":
+		return runSyncClear(state, args[1:])
+	case "-h", "--help", "help":
+		printSyncUsage(state.Out)
+		return 0
+	default:
+		code := reportError(state, usageErrorf("unknown sync command: %s", args[0]))
+		printSyncUsage(state.Err)
+		return code
+	}
+}
+
+func runSyncPush(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi sync push", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printSyncUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+	if state.Offline {
+		return reportError(state, usageErrorf("cannot push with --offline"))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+	j, err := state.journal()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	report, pushErr := journal.Push(ctx, client, j)
+	if err := printPushReport(state.Out, report, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	if pushErr != nil {
+		return reportError(state, fmt.Errorf("push stopped, %d queued: %w", report.Remaining, pushErr))
+	}
+	if report.Count(journal.StatusConflict)+report.Count(journal.StatusRejected) > 0 {
+		return exitError
+	}
+	return 0
+}
+
+func runSyncStatus(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi sync status", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printSyncUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+
+	j, err := state.journal()
+	if err != nil {
+		return reportError(state, err)
+	}
+	entries, err := j.Entries()
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := printJournalEntries(state.Out, entries, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+func runSyncClear(state *state, args []string) int {
+	fs := flag.NewFlagSet("todi sync clear", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var force bool
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&force, "force", false, "Skip confirmation")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printSyncUsage(state.Out)
+		return 0
+	}
+	if len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("unexpected arguments"))
+	}
+
+	j, err := state.journal()
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := confirmDelete(state, "queued writes in", j.Path, force); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if err := j.Clear(); err != nil {
+		return reportError(state, err)
+	}
+	writeLine(state.Out, "queue cleared")
+	return 0
+}
+
+// queueWrite appends a write to the offline journal and prints the entry.
+func queueWrite(state *state, op, id string, body map[string]any) int {
+	j, err := state.journal()
+	if err != nil {
+		return reportError(state, err)
+	}
+	entry, err := j.Append(op, id, body)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if err := printQueuedEntry(state.Out, entry, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	return 0
+}
+
+// resolveQueuedTaskID resolves a task identifier, matching tasks queued
+// offline by content before falling back to resolveTaskID.
+func resolveQueuedTaskID(ctx context.Context, state *state, client *todi.Client, identifier string, forceID bool) (string, error) {
+	if forceID || !state.Offline {
+		return resolveTaskID(ctx, client, identifier, forceID)
+	}
+	j, err := state.journal()
+	if err != nil {
+		return "", err
+	}
+	entries, err := j.Entries()
+	if err != nil {
+		return "", err
+	}
+	var matches []string
+	for _, entry := range entries {
+		if entry.Op == journal.OpTaskAdd && entry.Body["content"] == identifier {
+			matches = append(matches, entry.ID)
+		}
+	}
+	switch len(matches) {
+	case 0:
+		return resolveTaskID(ctx, client, identifier, forceID)
+	case 1:
+		return matches[0], nil
+	default:
+		return "", &todi.LookupError{Resource: "task", Field: "title", Query: identifier, Err: todi.ErrAmbiguous}
+	}
+}
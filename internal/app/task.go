@@ -2,7 +2,6 @@ package app
 
 import (
 	"context"
-	"fmt"
 	"strings"
 
 	"github.com/mattjefferson/todi/internal/todi"
@@ -41,15 +40,17 @@ func runTask(ctx context.Context, state *state, args []string) int {
 		return runTaskReopen(ctx, state, args[1:])
 	case "delete":
 		return runTaskDelete(ctx, state, args[1:])
+	case "move":
+		return runTaskMove(ctx, state, args[1:])
 	case "quick":
 		return runTaskQuick(ctx, state, args[1:])
 	case "-h", "--help", "help":
 		printTaskUsage(state.Out)
 		return 0
 	default:
-		writeLine(state.Err, "error: unknown task command:", args[0])
+		code := reportError(state, usageErrorf("unknown task command: %s", args[0]))
 		printTaskUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -69,14 +70,14 @@ func resolveTaskID(ctx context.Context, client *todi.Client, identifier string,
 		return "", err
 	}
 	if task.ID == "" {
-		return "", fmt.Errorf("task not found: %s", identifier)
+		return "", &todi.LookupError{Resource: "task", Field: "title", Query: identifier, Err: todi.ErrNotFound}
 	}
 	return task.ID, nil
 }
 
 func resolveProjectID(ctx context.Context, client *todi.Client, projectName, projectID string) (string, error) {
 	if projectName != "" && projectID != "" {
-		return "", fmt.Errorf("cannot use --project and --project-id together")
+		return "", usageErrorf("cannot use --project and --project-id together")
 	}
 	if projectID != "" {
 		return projectID, nil
@@ -120,6 +121,29 @@ func ensureQuickAddLabel(text, label string) string {
 	return strings.TrimSpace(text) + " #" + label
 }
 
+// applyQuickAddDefaults appends the default project (#name) and labels
+// (@name) to quick-add text that names no project or labels of its own.
+func applyQuickAddDefaults(text string, defaults taskDefaults) string {
+	if defaults.Project.Value != "" && !hasQuickAddToken(text, "#") {
+		text = strings.TrimSpace(text) + " #" + defaults.Project.Value
+	}
+	if !hasQuickAddToken(text, "@") {
+		for _, label := range mergeLabels(nil, defaults.Labels.Value) {
+			text = strings.TrimSpace(text) + " @" + label
+		}
+	}
+	return text
+}
+
+func hasQuickAddToken(text, prefix string) bool {
+	for _, field := range strings.Fields(text) {
+		if len(field) > len(prefix) && strings.HasPrefix(field, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func validateDueFlags(due, dueDate, dueDatetime string) error {
 	count := 0
 	if due != "" {
@@ -132,7 +156,7 @@ func validateDueFlags(due, dueDate, dueDatetime string) error {
 		count++
 	}
 	if count > 1 {
-		return fmt.Errorf("use only one of --due, --due-date, or --due-datetime")
+		return usageErrorf("use only one of --due, --due-date, or --due-datetime")
 	}
 	return nil
 }
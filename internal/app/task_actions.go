@@ -6,107 +6,98 @@ import (
 	"fmt"
 	"io"
 	"strings"
+
+	"github.com/mattjefferson/todi/internal/journal"
+	"github.com/mattjefferson/todi/internal/todi"
 )
 
 func runTaskClose(ctx context.Context, state *state, args []string) int {
-	id, raw, code := taskAction(ctx, state, "close", args, false)
-	if code != 0 {
-		return code
-	}
-	if state.Mode == modeJSON {
-		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
-		}
-		return 0
-	}
-	if _, err := fmt.Fprintf(state.Out, "closed %s\n", id); err != nil {
-		return 1
-	}
-	return 0
+	return taskAction(ctx, state, "close", args, false)
 }
 
 func runTaskReopen(ctx context.Context, state *state, args []string) int {
-	id, raw, code := taskAction(ctx, state, "reopen", args, false)
-	if code != 0 {
-		return code
-	}
-	if state.Mode == modeJSON {
-		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
-		}
-		return 0
-	}
-	if _, err := fmt.Fprintf(state.Out, "reopened %s\n", id); err != nil {
-		return 1
-	}
-	return 0
+	return taskAction(ctx, state, "reopen", args, false)
 }
 
 func runTaskDelete(ctx context.Context, state *state, args []string) int {
-	id, raw, code := taskAction(ctx, state, "delete", args, true)
-	if code != 0 {
-		return code
-	}
-	if state.Mode == modeJSON {
-		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
-		}
-		return 0
-	}
-	if _, err := fmt.Fprintf(state.Out, "deleted %s\n", id); err != nil {
-		return 1
-	}
-	return 0
+	return taskAction(ctx, state, "delete", args, true)
+}
+
+var taskActionPast = map[string]string{
+	"close":  "closed",
+	"reopen": "reopened",
+	"delete": "deleted",
 }
 
-func taskAction(ctx context.Context, state *state, action string, args []string, destructive bool) (string, []byte, int) {
+var taskActionOps = map[string]string{
+	"close":  journal.OpTaskClose,
+	"reopen": journal.OpTaskReopen,
+	"delete": journal.OpTaskDelete,
+}
+
+func taskAction(ctx context.Context, state *state, action string, args []string, destructive bool) int {
 	fs := flag.NewFlagSet("todi task "+action, flag.ContinueOnError)
 	fs.SetOutput(io.Discard)
 	var help bool
 	var forceID bool
 	var force bool
+	var bulk bulkFlags
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.BoolVar(&forceID, "id", false, "Treat argument as task ID")
 	if destructive {
 		fs.BoolVar(&force, "force", false, "Skip confirmation")
 	}
+	bulk.register(fs)
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return "", nil, 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printTaskUsage(state.Out)
-		return "", nil, 0
+		return 0
+	}
+	source, err := bulk.source()
+	if err != nil {
+		return reportError(state, err)
+	}
+	if source != "" {
+		if len(fs.Args()) > 0 {
+			return reportError(state, usageErrorf("cannot use --stdin or --ids-from with a task argument"))
+		}
+		return runBulk(ctx, state, bulk, source, bulkJob{
+			Past:        taskActionPast[action],
+			Op:          taskActionOps[action],
+			Apply:       taskActionFunc(action),
+			ForceID:     forceID,
+			Destructive: destructive,
+			Force:       force,
+		})
 	}
 	if len(fs.Args()) == 0 {
-		writeLine(state.Err, "error: task identifier required")
-		return "", nil, 2
+		return reportError(state, usageErrorf("task identifier required"))
 	}
 	identifier := strings.Join(fs.Args(), " ")
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return "", nil, 1
+		return reportError(state, err)
 	}
 
-	id, err := resolveTaskID(ctx, client, identifier, forceID)
+	id, err := resolveQueuedTaskID(ctx, state, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return "", nil, 1
+		return reportError(state, err)
 	}
 
 	if destructive {
 		if err := confirmDelete(state, "task", identifier, force); err != nil {
-			writeLine(state.Err, "error:", err)
-			return "", nil, 2
+			return reportError(state, usageError{err})
 		}
 	}
 
+	if state.Offline {
+		return queueWrite(state, taskActionOps[action], id, nil)
+	}
+
 	var raw []byte
 	switch action {
 	case "close":
@@ -116,11 +107,37 @@ func taskAction(ctx context.Context, state *state, action string, args []string,
 	case "delete":
 		raw, err = client.DeleteTask(ctx, id)
 	default:
-		return "", nil, 2
+		return 2
 	}
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return "", nil, 1
+		return reportError(state, err)
+	}
+	if state.Mode == modeJSON {
+		if err := printRawJSON(state.Out, raw); err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+	if _, err := fmt.Fprintf(state.Out, "%s %s\n", taskActionPast[action], id); err != nil {
+		return 1
+	}
+	return 0
+}
+
+// taskActionFunc performs action on one task for batch mode.
+func taskActionFunc(action string) func(context.Context, *todi.Client, string) error {
+	return func(ctx context.Context, client *todi.Client, id string) error {
+		var err error
+		switch action {
+		case "close":
+			_, err = client.CloseTask(ctx, id)
+		case "reopen":
+			_, err = client.ReopenTask(ctx, id)
+		case "delete":
+			_, err = client.DeleteTask(ctx, id)
+		default:
+			err = fmt.Errorf("unknown task action: %s", action)
+		}
+		return err
 	}
-	return id, raw, 0
 }
@@ -4,6 +4,8 @@ import (
 	"context"
 	"flag"
 	"io"
+
+	"github.com/mattjefferson/todi/internal/journal"
 )
 
 func runTaskAdd(ctx context.Context, state *state, args []string) int {
@@ -13,6 +15,10 @@ func runTaskAdd(ctx context.Context, state *state, args []string) int {
 	var description string
 	var projectName string
 	var projectID string
+	var section string
+	var sectionID string
+	var parent string
+	var parentID string
 	var labels stringSlice
 	var labelsCSV string
 	var priority int
@@ -24,11 +30,16 @@ func runTaskAdd(ctx context.Context, state *state, args []string) int {
 	var duration int
 	var durationUnit string
 	var deadlineDate string
+	var noDefaults bool
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.StringVar(&description, "description", "", "Task description")
 	fs.StringVar(&projectName, "project", "", "Project title (exact match)")
 	fs.StringVar(&projectID, "project-id", "", "Project ID")
+	fs.StringVar(&section, "section", "", "Section name (exact match)")
+	fs.StringVar(&sectionID, "section-id", "", "Section ID")
+	fs.StringVar(&parent, "parent", "", "Parent task title (exact match)")
+	fs.StringVar(&parentID, "parent-id", "", "Parent task ID")
 	fs.Var(&labels, "label", "Label (repeatable)")
 	fs.StringVar(&labelsCSV, "labels", "", "Labels (comma-separated)")
 	fs.IntVar(&priority, "priority", 0, "Priority 1-4")
@@ -40,10 +51,10 @@ func runTaskAdd(ctx context.Context, state *state, args []string) int {
 	fs.IntVar(&duration, "duration", 0, "Duration value")
 	fs.StringVar(&durationUnit, "duration-unit", "", "Duration unit (minute|day)")
 	fs.StringVar(&deadlineDate, "deadline-date", "", "Deadline date (YYYY-MM-DD)")
+	fs.BoolVar(&noDefaults, "no-defaults", false, "Ignore default_project and default_labels")
 
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printTaskUsage(state.Out)
@@ -51,33 +62,62 @@ func runTaskAdd(ctx context.Context, state *state, args []string) int {
 	}
 	content := joinArgs(fs.Args())
 	if content == "" {
-		writeLine(state.Err, "error: content required")
-		return 2
+		return reportError(state, usageErrorf("content required"))
+	}
+	if section != "" && sectionID != "" {
+		return reportError(state, usageErrorf("cannot use --section and --section-id together"))
+	}
+	if parent != "" && parentID != "" {
+		return reportError(state, usageErrorf("cannot use --parent and --parent-id together"))
+	}
+	labelsAll := mergeLabels(labels, labelsCSV)
+	if !noDefaults {
+		defaults, err := state.taskDefaults()
+		if err != nil {
+			return reportError(state, err)
+		}
+		// A section or parent already places the task, so the default
+		// project only applies when none of them is given.
+		if projectName == "" && projectID == "" && section == "" && sectionID == "" && parent == "" && parentID == "" {
+			projectName = defaults.Project.Value
+		}
+		if len(labelsAll) == 0 {
+			labelsAll = mergeLabels(nil, defaults.Labels.Value)
+		}
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+	sectionIDValue := sectionID
+	if section != "" {
+		sectionIDValue, err = resolveSectionIDFromIdentifier(ctx, client, section, false, projectIDValue)
+		if err != nil {
+			return reportError(state, err)
+		}
+	}
+	parentIDValue := parentID
+	if parent != "" {
+		parentIDValue, err = resolveQueuedTaskID(ctx, state, client, parent, false)
+		if err != nil {
+			return reportError(state, err)
+		}
 	}
 
-	labelsAll := mergeLabels(labels, labelsCSV)
 	if state.LabelCLI {
 		labelsAll = appendUniqueLabel(labelsAll, cliLabel)
 	}
 	if err := validateDueFlags(due, dueDate, dueDatetime); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if priority != 0 && (priority < 1 || priority > 4) {
-		writeLine(state.Err, "error: priority must be 1-4")
-		return 2
+		return reportError(state, usageErrorf("priority must be 1-4"))
 	}
 
 	body := map[string]any{"content": content}
@@ -87,6 +127,12 @@ func runTaskAdd(ctx context.Context, state *state, args []string) int {
 	if projectIDValue != "" {
 		body["project_id"] = projectIDValue
 	}
+	if sectionIDValue != "" {
+		body["section_id"] = sectionIDValue
+	}
+	if parentIDValue != "" {
+		body["parent_id"] = parentIDValue
+	}
 	if len(labelsAll) > 0 {
 		body["labels"] = labelsAll
 	}
@@ -118,22 +164,23 @@ func runTaskAdd(ctx context.Context, state *state, args []string) int {
 		body["deadline_date"] = deadlineDate
 	}
 
+	if state.Offline {
+		return queueWrite(state, journal.OpTaskAdd, journal.NewTempID(), body)
+	}
+
 	task, raw, err := client.CreateTask(ctx, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printTask(state.Out, task, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
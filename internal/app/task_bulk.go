@@ -0,0 +1,260 @@
+package app
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+const (
+	defaultBulkConcurrency = 4
+	defaultBulkRate        = 5
+)
+
+// bulkFlags are the flags that switch a task command to batch mode.
+type bulkFlags struct {
+	Stdin       bool
+	IDsFrom     string
+	Concurrency int
+	Rate        float64
+}
+
+func (b *bulkFlags) register(fs *flag.FlagSet) {
+	b.registerInput(fs)
+	fs.IntVar(&b.Concurrency, "concurrency", defaultBulkConcurrency, "Parallel requests in batch mode")
+	fs.Float64Var(&b.Rate, "rate", defaultBulkRate, "Max requests per second in batch mode (0 for no limit)")
+}
+
+// registerInput registers only the input flags, for commands that send a
+// batch in one request.
+func (b *bulkFlags) registerInput(fs *flag.FlagSet) {
+	fs.BoolVar(&b.Stdin, "stdin", false, "Read tasks from stdin, one per line")
+	fs.StringVar(&b.IDsFrom, "ids-from", "", "Read tasks from a file, one per line (- for stdin)")
+}
+
+// source returns where batch input comes from, or "" for a single task.
+func (b *bulkFlags) source() (string, error) {
+	if b.Stdin && b.IDsFrom != "" && b.IDsFrom != "-" {
+		return "", usageErrorf("cannot use --stdin with --ids-from")
+	}
+	if b.Stdin {
+		return "-", nil
+	}
+	return b.IDsFrom, nil
+}
+
+// bulkJob is one batch task command.
+type bulkJob struct {
+	// Past names the outcome, as in "closed 123".
+	Past string
+	// Op and Body are queued for each task with --offline.
+	Op   string
+	Body map[string]any
+	// Apply performs the action on one task.
+	Apply       func(ctx context.Context, client *todi.Client, id string) error
+	ForceID     bool
+	Destructive bool
+	Force       bool
+}
+
+// bulkResult is the per-item outcome of a batch task command.
+type bulkResult struct {
+	Input string `json:"input"`
+	ID    string `json:"id,omitempty"`
+	OK    bool   `json:"ok"`
+	Error string `json:"error,omitempty"`
+}
+
+// runBulk resolves every line of source to a task and applies job to each
+// through a bounded worker pool sharing one rate limiter. Names are resolved
+// against a single task listing. It exits non-zero if any item failed.
+func runBulk(ctx context.Context, state *state, flags bulkFlags, source string, job bulkJob) int {
+	if flags.Concurrency < 1 {
+		return reportError(state, usageErrorf("--concurrency must be at least 1"))
+	}
+	if flags.Rate < 0 {
+		return reportError(state, usageErrorf("--rate must not be negative"))
+	}
+	inputs, err := readBulkInput(source)
+	if err != nil {
+		return reportError(state, err)
+	}
+	if len(inputs) == 0 {
+		return reportError(state, usageErrorf("no tasks given on %s", bulkSourceName(source)))
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+	results, err := loadBulk(ctx, state, client, source, inputs, job.ForceID)
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	if job.Destructive {
+		label := fmt.Sprintf("%d tasks from", len(inputs))
+		if err := confirmDelete(state, label, bulkSourceName(source), job.Force); err != nil {
+			return reportError(state, usageError{err})
+		}
+	}
+
+	if state.Offline {
+		job.Past = "queued"
+		queueBulk(state, job, results)
+	} else {
+		client.Limiter = todi.NewRateLimiter(flags.Rate)
+		applyBulk(ctx, client, job, flags.Concurrency, results)
+	}
+
+	if err := printBulkResults(state.Out, state.Err, job.Past, results, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	for _, result := range results {
+		if !result.OK {
+			return exitError
+		}
+	}
+	return 0
+}
+
+// loadBulk returns a result per input with its task ID resolved.
+func loadBulk(ctx context.Context, state *state, client *todi.Client, source string, inputs []string, forceID bool) ([]bulkResult, error) {
+	if source == "-" {
+		// Stdin carries the task list, so ambiguous names cannot be asked.
+		client.Choose = nil
+	}
+	results := make([]bulkResult, len(inputs))
+	for i, input := range inputs {
+		results[i] = bulkResult{Input: input}
+	}
+	if err := resolveBulk(ctx, state, client, forceID, results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// resolveBulk fills in the task ID of each result, recording lookup
+// failures as errors.
+func resolveBulk(ctx context.Context, state *state, client *todi.Client, forceID bool, results []bulkResult) error {
+	if forceID || state.Offline {
+		for i := range results {
+			id, err := resolveQueuedTaskID(ctx, state, client, results[i].Input, forceID)
+			if err != nil {
+				results[i].Error = err.Error()
+				continue
+			}
+			results[i].ID = id
+		}
+		return nil
+	}
+	titles := make([]string, len(results))
+	for i := range results {
+		titles[i] = results[i].Input
+	}
+	tasks, errs, err := client.FindTasksByContent(ctx, titles)
+	if err != nil {
+		return err
+	}
+	for i := range results {
+		if errs[i] != nil {
+			results[i].Error = errs[i].Error()
+			continue
+		}
+		results[i].ID = tasks[i].ID
+	}
+	return nil
+}
+
+// applyBulk runs job on every resolved result with up to concurrency
+// workers.
+func applyBulk(ctx context.Context, client *todi.Client, job bulkJob, concurrency int, results []bulkResult) {
+	work := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < concurrency; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				if err := job.Apply(ctx, client, results[i].ID); err != nil {
+					results[i].Error = err.Error()
+					continue
+				}
+				results[i].OK = true
+			}
+		}()
+	}
+	for i := range results {
+		if results[i].ID != "" {
+			work <- i
+		}
+	}
+	close(work)
+	wg.Wait()
+}
+
+// queueBulk records each resolved task in the offline journal.
+func queueBulk(state *state, job bulkJob, results []bulkResult) {
+	j, err := state.journal()
+	for i := range results {
+		if results[i].ID == "" {
+			continue
+		}
+		if err == nil {
+			_, err = j.Append(job.Op, results[i].ID, job.Body)
+		}
+		if err != nil {
+			results[i].Error = err.Error()
+			continue
+		}
+		results[i].OK = true
+	}
+}
+
+// readBulkInput reads one task per line from a file or, for "-", stdin,
+// skipping blank lines and # comments.
+func readBulkInput(source string) ([]string, error) {
+	if source == "-" {
+		return readLines(os.Stdin)
+	}
+	f, err := os.Open(source)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := f.Close(); err != nil {
+			return
+		}
+	}()
+	return readLines(f)
+}
+
+func readLines(r io.Reader) ([]string, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return lines, nil
+}
+
+func bulkSourceName(source string) string {
+	if source == "-" {
+		return "stdin"
+	}
+	return source
+}
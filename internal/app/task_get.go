@@ -16,33 +16,28 @@ func runTaskGet(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.BoolVar(&forceID, "id", false, "Treat argument as task ID")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printTaskUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) == 0 {
-		writeLine(state.Err, "error: task identifier required")
-		return 2
+		return reportError(state, usageErrorf("task identifier required"))
 	}
 	identifier := strings.Join(fs.Args(), " ")
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	task, err := resolveTask(ctx, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := printTask(state.Out, task, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -6,6 +6,8 @@ import (
 	"io"
 	"strconv"
 	"strings"
+
+	"github.com/mattjefferson/todi/internal/todi"
 )
 
 func runTaskList(ctx context.Context, state *state, args []string) int {
@@ -17,6 +19,10 @@ func runTaskList(ctx context.Context, state *state, args []string) int {
 	var cursor string
 	var all bool
 	var label string
+	var filter string
+	var lang string
+	var tree bool
+	var view listView
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.StringVar(&projectName, "project", "", "Project title (exact match)")
@@ -24,9 +30,12 @@ func runTaskList(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&cursor, "cursor", "", "Pagination cursor")
 	fs.BoolVar(&all, "all", false, "Fetch all pages")
 	fs.StringVar(&label, "label", "", "Label name")
+	fs.StringVar(&filter, "filter", "", "Todoist filter query")
+	fs.StringVar(&lang, "lang", "", "Filter query language code")
+	fs.BoolVar(&tree, "tree", false, "Nest subtasks under their parents, grouped by section")
+	view.register(fs)
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printTaskUsage(state.Out)
@@ -34,16 +43,26 @@ func runTaskList(ctx context.Context, state *state, args []string) int {
 	}
 	if len(fs.Args()) > 0 {
 		if projectName != "" {
-			writeLine(state.Err, "error: project specified twice")
-			return 2
+			return reportError(state, usageErrorf("project specified twice"))
 		}
 		projectName = strings.Join(fs.Args(), " ")
 	}
+	if filter != "" && (projectName != "" || label != "") {
+		return reportError(state, usageErrorf("cannot use --filter with a project or --label"))
+	}
+	if lang != "" && filter == "" {
+		return reportError(state, usageErrorf("--lang requires --filter"))
+	}
+	if tree && view.active() {
+		return reportError(state, usageErrorf("cannot use --fields or --sort with --tree"))
+	}
+	if _, _, err := view.parse(taskView); err != nil {
+		return reportError(state, usageError{err})
+	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	params := map[string]string{}
@@ -59,41 +78,91 @@ func runTaskList(ctx context.Context, state *state, args []string) int {
 	if projectName != "" {
 		projectID, err := client.FindProjectIDByName(ctx, projectName)
 		if err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		params["project_id"] = projectID
 	}
 
-	if all {
-		tasks, err := client.ListTasksAll(ctx, params)
+	listPage := client.ListTasks
+	listAll := client.ListTasksAll
+	listEach := client.EachTaskPage
+	if filter != "" {
+		params["query"] = filter
+		params["lang"] = lang
+		listPage = client.FilterTasks
+		listAll = client.FilterTasksAll
+		listEach = func(ctx context.Context, params map[string]string, fn func([]todi.Task) error) error {
+			return todi.EachPage(ctx, params, client.FilterTasks, fn)
+		}
+	}
+
+	// Line-oriented outputs print each page as it arrives.
+	if all && state.Stream && !tree && !view.active() {
+		err := listEach(ctx, params, func(page []todi.Task) error {
+			return printTasks(state.Out, page, state.Mode)
+		})
+		if err != nil {
+			return reportError(state, err)
+		}
+		return 0
+	}
+
+	if all || tree {
+		tasks, err := listAll(ctx, params)
 		if err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
-		if err := printTasks(state.Out, tasks, state.Mode); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+		switch {
+		case tree:
+			err = printListTree(ctx, client, state, tasks, params["project_id"])
+		case view.active():
+			err = view.print(ctx, state, client, taskView, tasks, nil)
+		default:
+			err = printTasks(state.Out, tasks, state.Mode)
+		}
+		if err != nil {
+			return reportError(state, err)
 		}
 		return 0
 	}
 
-	tasks, next, err := client.ListTasks(ctx, params)
+	tasks, next, err := listPage(ctx, params)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+	if view.active() {
+		if err := view.print(ctx, state, client, taskView, tasks, &next); err != nil {
+			return reportError(state, err)
+		}
+		return 0
 	}
 	if state.Mode == modeJSON {
-		payload := map[string]any{"results": tasks, "next_cursor": next}
+		payload := map[string]any{"results": withTaskURLs(tasks), "next_cursor": next}
 		if err := printJSON(state.Out, payload); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printTasks(state.Out, tasks, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
+
+// printListTree fetches the projects and sections needed to group tasks and
+// prints them as a tree.
+func printListTree(ctx context.Context, client *todi.Client, state *state, tasks []todi.Task, projectID string) error {
+	projects, err := client.ListProjectsAll(ctx)
+	if err != nil {
+		return err
+	}
+	sectionParams := map[string]string{}
+	if projectID != "" {
+		sectionParams["project_id"] = projectID
+	}
+	sections, err := client.ListSectionsAll(ctx, sectionParams)
+	if err != nil {
+		return err
+	}
+	return printTaskTree(state.Out, groupTaskTree(tasks, projects, sections), state.Mode)
+}
@@ -0,0 +1,193 @@
+package app
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+func runTaskMove(ctx context.Context, state *state, args []string) int {
+	fs := flag.NewFlagSet("todi task move", flag.ContinueOnError)
+	fs.SetOutput(io.Discard)
+	var help bool
+	var forceID bool
+	var bulk bulkFlags
+	var projectName string
+	var projectID string
+	var section string
+	var sectionID string
+	var parent string
+	var parentID string
+	fs.BoolVar(&help, "help", false, "Show help")
+	fs.BoolVar(&help, "h", false, "Show help")
+	fs.BoolVar(&forceID, "id", false, "Treat argument as task ID")
+	bulk.registerInput(fs)
+	fs.StringVar(&projectName, "project", "", "Target project title (exact match)")
+	fs.StringVar(&projectID, "project-id", "", "Target project ID")
+	fs.StringVar(&section, "section", "", "Target section name (exact match)")
+	fs.StringVar(&sectionID, "section-id", "", "Target section ID")
+	fs.StringVar(&parent, "parent", "", "Target parent task title (exact match)")
+	fs.StringVar(&parentID, "parent-id", "", "Target parent task ID")
+	if err := fs.Parse(args); err != nil {
+		return reportError(state, usageError{err})
+	}
+	if help {
+		printTaskUsage(state.Out)
+		return 0
+	}
+	source, err := bulk.source()
+	if err != nil {
+		return reportError(state, err)
+	}
+	identifier := joinArgs(fs.Args())
+	if source != "" && identifier != "" {
+		return reportError(state, usageErrorf("cannot use --stdin or --ids-from with a task argument"))
+	}
+	if source == "" && identifier == "" {
+		return reportError(state, usageErrorf("task identifier required"))
+	}
+	if section != "" && sectionID != "" {
+		return reportError(state, usageErrorf("cannot use --section and --section-id together"))
+	}
+	if parent != "" && parentID != "" {
+		return reportError(state, usageErrorf("cannot use --parent and --parent-id together"))
+	}
+	hasProject := projectName != "" || projectID != ""
+	hasSection := section != "" || sectionID != ""
+	hasParent := parent != "" || parentID != ""
+	if hasParent && (hasProject || hasSection) {
+		return reportError(state, usageErrorf("cannot combine --parent with --project or --section"))
+	}
+	if !hasProject && !hasSection && !hasParent {
+		return reportError(state, usageErrorf("--project, --section or --parent required"))
+	}
+
+	var inputs []string
+	if source != "" {
+		inputs, err = readBulkInput(source)
+		if err != nil {
+			return reportError(state, err)
+		}
+		if len(inputs) == 0 {
+			return reportError(state, usageErrorf("no tasks given on %s", bulkSourceName(source)))
+		}
+	}
+
+	client, err := state.client()
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	target, err := resolveMoveTarget(ctx, client, projectName, projectID, section, sectionID, parent, parentID)
+	if err != nil {
+		return reportError(state, err)
+	}
+
+	if source == "" {
+		id, err := resolveTaskID(ctx, client, identifier, forceID)
+		if err != nil {
+			return reportError(state, err)
+		}
+		results, err := client.MoveTasks(ctx, []string{id}, target)
+		if err == nil && len(results) == 1 {
+			err = results[0].Err
+		}
+		if err != nil {
+			return reportError(state, err)
+		}
+		var werr error
+		switch state.Mode {
+		case modeJSON:
+			werr = printJSON(state.Out, map[string]any{"id": id, "moved": true})
+		case modePlain:
+			_, werr = fmt.Fprintln(state.Out, id)
+		default:
+			_, werr = fmt.Fprintf(state.Out, "moved %s\n", id)
+		}
+		if werr != nil {
+			return reportError(state, werr)
+		}
+		return 0
+	}
+
+	results, err := loadBulk(ctx, state, client, source, inputs, forceID)
+	if err != nil {
+		return reportError(state, err)
+	}
+	moveBulk(ctx, client, target, results)
+	if err := printBulkResults(state.Out, state.Err, "moved", results, state.Mode); err != nil {
+		return reportError(state, err)
+	}
+	for _, result := range results {
+		if !result.OK {
+			return exitError
+		}
+	}
+	return 0
+}
+
+// moveBulk moves every resolved result in one batch of Sync commands,
+// recording each outcome.
+func moveBulk(ctx context.Context, client *todi.Client, target todi.MoveTarget, results []bulkResult) {
+	var ids []string
+	var index []int
+	for i := range results {
+		if results[i].ID != "" {
+			ids = append(ids, results[i].ID)
+			index = append(index, i)
+		}
+	}
+	if len(ids) == 0 {
+		return
+	}
+	moved, err := client.MoveTasks(ctx, ids, target)
+	for n, i := range index {
+		switch {
+		case n < len(moved) && moved[n].Err != nil:
+			results[i].Error = moved[n].Err.Error()
+		case n < len(moved):
+			results[i].OK = true
+		default:
+			results[i].Error = err.Error()
+		}
+	}
+}
+
+// resolveMoveTarget turns the move flags into a target, checking that an
+// explicit section belongs to an explicit project.
+func resolveMoveTarget(ctx context.Context, client *todi.Client, projectName, projectID, section, sectionID, parent, parentID string) (todi.MoveTarget, error) {
+	if parent != "" || parentID != "" {
+		if parentID == "" {
+			id, err := resolveTaskID(ctx, client, parent, false)
+			if err != nil {
+				return todi.MoveTarget{}, err
+			}
+			parentID = id
+		}
+		return todi.MoveTarget{ParentID: parentID}, nil
+	}
+
+	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
+	if err != nil {
+		return todi.MoveTarget{}, err
+	}
+	if section == "" && sectionID == "" {
+		return todi.MoveTarget{ProjectID: projectIDValue}, nil
+	}
+
+	identifier, forceID := section, false
+	if sectionID != "" {
+		identifier, forceID = sectionID, true
+	}
+	resolved, err := resolveSection(ctx, client, identifier, forceID, projectIDValue)
+	if err != nil {
+		return todi.MoveTarget{}, err
+	}
+	if projectIDValue != "" && resolved.ProjectID != projectIDValue {
+		return todi.MoveTarget{}, usageErrorf("section %s is not in project %s", resolved.ID, projectIDValue)
+	}
+	return todi.MoveTarget{SectionID: resolved.ID}, nil
+}
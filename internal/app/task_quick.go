@@ -14,16 +14,17 @@ func runTaskQuick(ctx context.Context, state *state, args []string) int {
 	var reminder string
 	var autoReminder bool
 	var meta bool
+	var noDefaults bool
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.StringVar(&note, "note", "", "Note")
 	fs.StringVar(&reminder, "reminder", "", "Reminder")
 	fs.BoolVar(&autoReminder, "auto-reminder", false, "Auto reminder")
 	fs.BoolVar(&meta, "meta", false, "Include metadata")
+	fs.BoolVar(&noDefaults, "no-defaults", false, "Ignore default_project and default_labels")
 
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printTaskUsage(state.Out)
@@ -31,8 +32,14 @@ func runTaskQuick(ctx context.Context, state *state, args []string) int {
 	}
 	text := joinArgs(fs.Args())
 	if text == "" {
-		writeLine(state.Err, "error: quick-add text required")
-		return 2
+		return reportError(state, usageErrorf("quick-add text required"))
+	}
+	if !noDefaults {
+		defaults, err := state.taskDefaults()
+		if err != nil {
+			return reportError(state, err)
+		}
+		text = applyQuickAddDefaults(text, defaults)
 	}
 	if state.LabelCLI {
 		text = ensureQuickAddLabel(text, cliLabel)
@@ -40,8 +47,7 @@ func runTaskQuick(ctx context.Context, state *state, args []string) int {
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	body := map[string]any{"text": text}
@@ -60,14 +66,12 @@ func runTaskQuick(ctx context.Context, state *state, args []string) int {
 
 	task, raw, err := client.QuickAdd(ctx, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -76,8 +80,7 @@ func runTaskQuick(ctx context.Context, state *state, args []string) int {
 		return 0
 	}
 	if err := printTask(state.Out, task, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -0,0 +1,82 @@
+package app
+
+import (
+	"slices"
+	"sort"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+// taskGroup holds the tasks of one project section for tree output.
+type taskGroup struct {
+	Title string
+	Tasks []treeEntry[todi.Task]
+}
+
+// groupTaskTree splits tasks by project and section, in the order the apps
+// show them, and nests subtasks under their parents within each group.
+func groupTaskTree(tasks []todi.Task, projects []todi.Project, sections []todi.Section) []taskGroup {
+	projectRank := make(map[string]int, len(projects))
+	projectName := make(map[string]string, len(projects))
+	for i, project := range projects {
+		projectRank[project.ID] = i
+		projectName[project.ID] = project.Name
+	}
+	sectionOrder := make(map[string]int, len(sections))
+	sectionName := make(map[string]string, len(sections))
+	for _, section := range sections {
+		sectionOrder[section.ID] = section.SectionOrder
+		sectionName[section.ID] = section.Name
+	}
+
+	type groupKey struct{ project, section string }
+	byGroup := map[groupKey][]todi.Task{}
+	var keys []groupKey
+	for _, task := range tasks {
+		key := groupKey{task.ProjectID, task.SectionID}
+		if _, ok := byGroup[key]; !ok {
+			keys = append(keys, key)
+		}
+		byGroup[key] = append(byGroup[key], task)
+	}
+	rank := func(id string) int {
+		if r, ok := projectRank[id]; ok {
+			return r
+		}
+		return len(projects)
+	}
+	sort.SliceStable(keys, func(i, j int) bool {
+		a, b := keys[i], keys[j]
+		if a.project != b.project {
+			return rank(a.project) < rank(b.project)
+		}
+		if (a.section == "") != (b.section == "") {
+			return a.section == ""
+		}
+		return sectionOrder[a.section] < sectionOrder[b.section]
+	})
+
+	taskID := func(t todi.Task) string { return t.ID }
+	parentID := func(t todi.Task) string { return t.ParentID }
+	groups := make([]taskGroup, 0, len(keys))
+	for _, key := range keys {
+		title := projectName[key.project]
+		if title == "" {
+			title = key.project
+		}
+		if title == "" {
+			title = "(no project)"
+		}
+		if key.section != "" {
+			name := sectionName[key.section]
+			if name == "" {
+				name = key.section
+			}
+			title += " / " + name
+		}
+		members := slices.Clone(byGroup[key])
+		sort.SliceStable(members, func(i, j int) bool { return members[i].ChildOrder < members[j].ChildOrder })
+		groups = append(groups, taskGroup{Title: title, Tasks: walkTree(members, taskID, parentID)})
+	}
+	return groups
+}
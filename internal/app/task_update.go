@@ -5,6 +5,9 @@ import (
 	"flag"
 	"io"
 	"strings"
+
+	"github.com/mattjefferson/todi/internal/journal"
+	"github.com/mattjefferson/todi/internal/todi"
 )
 
 func runTaskUpdate(ctx context.Context, state *state, args []string) int {
@@ -25,6 +28,7 @@ func runTaskUpdate(ctx context.Context, state *state, args []string) int {
 	var duration int
 	var durationUnit string
 	var deadlineDate string
+	var bulk bulkFlags
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	fs.BoolVar(&forceID, "id", false, "Treat argument as task ID")
@@ -41,29 +45,33 @@ func runTaskUpdate(ctx context.Context, state *state, args []string) int {
 	fs.IntVar(&duration, "duration", 0, "Duration value")
 	fs.StringVar(&durationUnit, "duration-unit", "", "Duration unit (minute|day)")
 	fs.StringVar(&deadlineDate, "deadline-date", "", "Deadline date (YYYY-MM-DD)")
+	bulk.register(fs)
 
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printTaskUsage(state.Out)
 		return 0
 	}
-	if len(fs.Args()) == 0 {
-		writeLine(state.Err, "error: task identifier required")
-		return 2
+	source, err := bulk.source()
+	if err != nil {
+		return reportError(state, err)
+	}
+	if source != "" && len(fs.Args()) > 0 {
+		return reportError(state, usageErrorf("cannot use --stdin or --ids-from with a task argument"))
+	}
+	if source == "" && len(fs.Args()) == 0 {
+		return reportError(state, usageErrorf("task identifier required"))
 	}
 	identifier := strings.Join(fs.Args(), " ")
 
 	labelsAll := mergeLabels(labels, labelsCSV)
 	if err := validateDueFlags(due, dueDate, dueDatetime); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if priority != 0 && (priority < 1 || priority > 4) {
-		writeLine(state.Err, "error: priority must be 1-4")
-		return 2
+		return reportError(state, usageErrorf("priority must be 1-4"))
 	}
 
 	body := map[string]any{}
@@ -104,31 +112,42 @@ func runTaskUpdate(ctx context.Context, state *state, args []string) int {
 		body["deadline_date"] = deadlineDate
 	}
 	if len(body) == 0 {
-		writeLine(state.Err, "error: no updates specified")
-		return 2
+		return reportError(state, usageErrorf("no updates specified"))
+	}
+	if source != "" {
+		return runBulk(ctx, state, bulk, source, bulkJob{
+			Past: "updated",
+			Op:   journal.OpTaskUpdate,
+			Body: body,
+			Apply: func(ctx context.Context, client *todi.Client, id string) error {
+				_, _, err := client.UpdateTask(ctx, id, body)
+				return err
+			},
+			ForceID: forceID,
+		})
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
-	id, err := resolveTaskID(ctx, client, identifier, forceID)
+	id, err := resolveQueuedTaskID(ctx, state, client, identifier, forceID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
+	}
+
+	if state.Offline {
+		return queueWrite(state, journal.OpTaskUpdate, id, body)
 	}
 
 	task, raw, err := client.UpdateTask(ctx, id, body)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
@@ -137,8 +156,7 @@ func runTaskUpdate(ctx context.Context, state *state, args []string) int {
 		return 0
 	}
 	if err := printTask(state.Out, task, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -0,0 +1,77 @@
+package app
+
+import "encoding/json"
+
+// treeEntry is one row of a depth-first walk over a parent/child hierarchy.
+type treeEntry[T any] struct {
+	Item  T
+	Depth int
+}
+
+// walkTree orders items depth-first under their parents. Items whose parent
+// is not in the slice are treated as roots. Siblings keep their input order.
+func walkTree[T any](items []T, id, parent func(T) string) []treeEntry[T] {
+	present := make(map[string]bool, len(items))
+	for _, item := range items {
+		present[id(item)] = true
+	}
+	children := map[string][]int{}
+	var roots []int
+	for i, item := range items {
+		if p := parent(item); p != "" && present[p] && p != id(item) {
+			children[p] = append(children[p], i)
+			continue
+		}
+		roots = append(roots, i)
+	}
+
+	entries := make([]treeEntry[T], 0, len(items))
+	visited := make([]bool, len(items))
+	var visit func(i, depth int)
+	visit = func(i, depth int) {
+		if visited[i] {
+			return
+		}
+		visited[i] = true
+		entries = append(entries, treeEntry[T]{Item: items[i], Depth: depth})
+		for _, child := range children[id(items[i])] {
+			visit(child, depth+1)
+		}
+	}
+	for _, i := range roots {
+		visit(i, 0)
+	}
+	// Items caught in a parent cycle are unreachable from any root.
+	for i := range items {
+		visit(i, 0)
+	}
+	return entries
+}
+
+// nestTree returns items as JSON objects with their children nested under
+// a "children" key, in walkTree order.
+func nestTree[T any](items []T, id, parent func(T) string) ([]map[string]any, error) {
+	entries := walkTree(items, id, parent)
+	var roots []map[string]any
+	var stack []map[string]any
+	for _, entry := range entries {
+		data, err := json.Marshal(entry.Item)
+		if err != nil {
+			return nil, err
+		}
+		var node map[string]any
+		if err := json.Unmarshal(data, &node); err != nil {
+			return nil, err
+		}
+		node["children"] = []map[string]any{}
+		stack = stack[:entry.Depth]
+		if entry.Depth == 0 {
+			roots = append(roots, node)
+		} else {
+			parentNode := stack[entry.Depth-1]
+			parentNode["children"] = append(parentNode["children"].([]map[string]any), node)
+		}
+		stack = append(stack, node)
+	}
+	return roots, nil
+}
@@ -22,9 +22,9 @@ func runUpload(ctx context.Context, state *state, args []string) int {
 		printUploadUsage(state.Out)
 		return 0
 	default:
-		writeLine(state.Err, "error: unknown upload command:", args[0])
+		code := reportError(state, usageErrorf("unknown upload command: %s", args[0]))
 		printUploadUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -41,8 +41,7 @@ func runUploadAdd(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&projectID, "project-id", "", "Project ID")
 	fs.StringVar(&name, "name", "", "Override file name")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printUploadUsage(state.Out)
@@ -50,37 +49,31 @@ func runUploadAdd(ctx context.Context, state *state, args []string) int {
 	}
 	path := strings.TrimSpace(strings.Join(fs.Args(), " "))
 	if path == "" {
-		writeLine(state.Err, "error: file path required")
-		return 2
+		return reportError(state, usageErrorf("file path required"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	projectIDValue, err := resolveProjectID(ctx, client, projectName, projectID)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	upload, raw, err := client.UploadFile(ctx, path, name, projectIDValue)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
 	if err := printUpload(state.Out, upload, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -96,8 +89,7 @@ func runUploadDelete(ctx context.Context, state *state, args []string) int {
 	fs.StringVar(&fileURL, "file-url", "", "File URL")
 	fs.BoolVar(&force, "force", false, "Skip confirmation")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printUploadUsage(state.Out)
@@ -105,36 +97,30 @@ func runUploadDelete(ctx context.Context, state *state, args []string) int {
 	}
 	if len(fs.Args()) > 0 {
 		if fileURL != "" {
-			writeLine(state.Err, "error: file url specified twice")
-			return 2
+			return reportError(state, usageErrorf("file url specified twice"))
 		}
 		fileURL = strings.TrimSpace(strings.Join(fs.Args(), " "))
 	}
 	if fileURL == "" {
-		writeLine(state.Err, "error: file url required")
-		return 2
+		return reportError(state, usageErrorf("file url required"))
 	}
 
 	if err := confirmDelete(state, "upload", fileURL, force); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	raw, err := client.DeleteUpload(ctx, fileURL)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if state.Mode == modeJSON {
 		if err := printRawJSON(state.Out, raw); err != nil {
-			writeLine(state.Err, "error:", err)
-			return 1
+			return reportError(state, err)
 		}
 		return 0
 	}
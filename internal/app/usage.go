@@ -22,7 +22,16 @@ COMMANDS:
   section Manage sections
   user    Manage user info
   auth    Manage auth token
+  profile Manage named profiles
   config  Manage config
+  cache   Manage local cache
+  sync    Replay writes queued with --offline
+  completed List completed tasks
+  reminder Manage task reminders
+  filter  Manage and run saved filters
+  today   Tasks due today, plus overdue
+  upcoming Tasks due in the next days, plus overdue
+  overdue Overdue tasks
 
 GLOBAL FLAGS:
   -h, --help        Show help
@@ -32,19 +41,41 @@ GLOBAL FLAGS:
   --json            JSON output
   --plain           Plain output
   --no-input        Disable prompts
-  --no-color        Disable color
+  --no-color        Disable color (also NO_COLOR)
   --config <path>   Config path override
+  --profile <name>  Profile to use (also TODI_PROFILE)
   --api-base <url>  API base (default https://api.todoist.com)
   --label-cli       Add label 'cli' to created tasks
+  --retries <n>     Max API retries on 429/5xx (default 3, 0 disables)
+  --retry-max-wait <dur>  Max wait between retries (default 30s)
+  --no-cache        Bypass the local cache
+  --refresh         Refetch cached data and rewrite the cache
+  --offline         Queue task/comment writes locally (see todi sync)
+  --format <tmpl>   Go template per result, or @name from config
+  -o, --output <fmt>  human|wide|plain|json|csv|tsv|ndjson|yaml
 
 OUTPUT MODES:
   default           Human-friendly tables
   --plain           Tab-delimited output for scripts
-  --json            Structured JSON output
+  --json            Structured JSON output (errors as JSON on stderr)
+  --output wide     Human tables; task lists add project, section, parent,
+                    order, duration, assignee and timestamp columns
+  --output csv|tsv  Results with a header row; columns are the JSON keys
+  --output ndjson   One compact JSON object per result
+  --output yaml     The JSON document as YAML
+  --format <tmpl>   Go template over the JSON fields of each result, e.g.
+                    '{{.id}}\t{{priority .priority}}\t{{.content | truncate 40}}'
+                    Funcs: due, join, priority, json, truncate, date
+
+EXIT CODES:
+  0 ok, 1 error, 2 usage, 3 auth, 4 not found, 5 ambiguous name,
+  6 rate limited, 7 validation
 
 AUTH:
   todi auth login            Save token to config
   TODOIST_TOKEN                 Overrides token in config
+  todi auth login --profile <n> Save token to a named profile
+  TODI_PROFILE                  Selects the profile (like --profile)
   todi config path           Print config file path
 
 NOTES:
@@ -70,18 +101,30 @@ USAGE:
   todi close <task>
   todi reopen <task>
   todi delete <task>
+  todi move <task>
   todi quick <text>
 
 FLAGS (list):
   --project <title>        Project title (exact match)
   --label <name>           Filter by label
+  --filter <query>         Todoist filter query (e.g. "today | overdue")
+  --lang <code>            Language of the filter query
   --limit <n>              Max tasks per page (1-200)
   --cursor <cursor>        Pagination cursor
   --all                    Fetch all pages
+  --fields <a,b>           Columns to show (JSON keys, plus project/section names)
+  --sort <a,-b>            Sort by fields (-field for descending)
+  --tree                   Nest subtasks, grouped by section (implies --all)
 
 FLAGS (get/close/reopen/delete):
   --id                     Treat argument as task ID
 
+FLAGS (update/close/reopen/delete):
+  --stdin                  Read tasks from stdin, one title (or ID with --id) per line
+  --ids-from <path>        Read tasks from a file (- for stdin)
+  --concurrency <n>        Parallel requests with --stdin/--ids-from (default 4)
+  --rate <n>               Max requests per second across them (default 5, 0 for no limit)
+
 FLAGS (delete):
   --force                  Skip confirmation
 
@@ -89,6 +132,10 @@ FLAGS (add):
   --description <text>     Task description
   --project <title>        Project title (exact match)
   --project-id <id>        Project ID
+  --section <name>         Section name (exact match, within --project)
+  --section-id <id>        Section ID
+  --parent <title>         Parent task title (exact match)
+  --parent-id <id>         Parent task ID
   --label <name>           Label (repeatable)
   --labels <a,b>           Labels (comma-separated)
   --priority <1-4>         Task priority
@@ -100,6 +147,7 @@ FLAGS (add):
   --duration <n>           Duration value
   --duration-unit <unit>   Duration unit (minute|day)
   --deadline-date <date>   Deadline date (YYYY-MM-DD)
+  --no-defaults            Ignore default_project and default_labels
 
 FLAGS (update):
   --id                     Treat argument as task ID
@@ -117,22 +165,57 @@ FLAGS (update):
   --duration-unit <unit>   Duration unit (minute|day)
   --deadline-date <date>   Deadline date (YYYY-MM-DD)
 
+FLAGS (move):
+  --id                     Treat argument as task ID
+  --stdin                  Read tasks from stdin, one title (or ID with --id) per line
+  --ids-from <path>        Read tasks from a file (- for stdin)
+  --project <title>        Target project title (exact match)
+  --project-id <id>        Target project ID
+  --section <name>         Target section name (exact match, within --project)
+  --section-id <id>        Target section ID
+  --parent <title>         Target parent task title (exact match)
+  --parent-id <id>         Target parent task ID
+
 FLAGS (quick):
   --note <text>            Add note
   --reminder <text>        Reminder
   --auto-reminder          Auto reminder
   --meta                   Include metadata
+  --no-defaults            Ignore default_project and default_labels
 
 EXAMPLES:
   todi list
   todi list "Inbox" --all
   todi add "Write docs" --project "Docs"
+  todi add "Proofread" --parent "Write docs"
+  todi list "Docs" --tree
+  todi list --filter "today | overdue"
+  todi list --filter "#Work & p1" --all
   todi update "Write docs" --content "Write help" --priority 2
   todi close "Write docs"
+  todi list --filter "overdue" --plain | cut -f1 | todi close --id --stdin
+  todi move "Write docs" --project "Work" --section "Backlog"
+  todi list --filter "#Inbox & @triage" --plain | cut -f1 | todi move --id --stdin --project "Work"
 
 NOTES:
   Task commands can also be called with the "task" prefix.
   <task> accepts exact task title unless --id is set.
+  move takes --parent or a project and/or section; a section given with a
+  project must belong to it. With --stdin or --ids-from, move resolves every
+  title against one task listing, moves them in one batch and reports each
+  task as update does.
+  update, close, reopen and delete with --stdin or --ids-from resolve every
+  title against one task listing, then run through a worker pool. They report
+  each task (errors on stderr; --plain prints input, id, ok|error and
+  message per line; --json lists every result) and exit non-zero if any
+  task failed. delete needs --force when reading stdin.
+  list --tree prints a table per project section with subtasks indented;
+  --plain adds a trailing depth column and --json nests "children".
+  Priorities print as in the apps: p1 is urgent (API priority 4).
+  --plain task columns: id, content, due, priority, project_id, section_id,
+  parent_id, child_order, labels, deadline, duration, assignee_id,
+  assigner_id, added_at, updated_at, completed_at, comment_count,
+  is_recurring, url.
 `); err != nil {
 		return
 	}
@@ -142,13 +225,79 @@ func printAuthUsage(out io.Writer) {
 	if _, err := fmt.Fprint(out, `todi auth - auth commands
 
 USAGE:
-  todi auth login
+  todi auth login [--profile <name>]
+  todi auth login --oauth [--client-id <id>] [--client-secret <secret>]
+                  [--scopes <list>] [--port <n>] [--no-browser]
   todi auth logout
   todi auth status
+  todi auth migrate [--to <store>]
+
+FLAGS (login):
+  --profile <name>         Save the token to this profile, creating it
+                           (default: the selected profile)
+  --no-verify              Save the token without checking it against the API
+  --oauth                  Log in through the browser (OAuth2 authorization code)
+  --client-id <id>         OAuth client ID (also TODI_OAUTH_CLIENT_ID, oauth_client_id)
+  --client-secret <secret> OAuth client secret (also TODI_OAUTH_CLIENT_SECRET,
+                           oauth_client_secret)
+  --scopes <list>          Comma-separated scopes (default: oauth_scopes, or
+                           data:read_write,data:delete,project:delete)
+  --auth-base <url>        OAuth server (also TODOIST_AUTH_BASE, auth_base;
+                           default https://todoist.com)
+  --port <n>               Loopback port for the redirect (default: any free port)
+  --no-browser             Only print the authorize URL
+  --timeout <dur>          How long to wait for the redirect (default 5m)
+
+FLAGS (migrate):
+  --to <store>             Target credential store
+                           (default: credential_store)
+
+STORES:
+  secret-service           Secret Service keyring via secret-tool (Linux)
+  pass                     pass password store
+  file                     Encrypted credentials.enc next to the config;
+                           passphrase from TODI_CREDENTIALS_PASSPHRASE
+  plaintext                Token in the config file
+
+NOTES:
+  login prompts for a token (TTY required) and saves it to credential_store.
+  login --oauth needs no TTY: it serves http://127.0.0.1:<port>/callback,
+  opens the authorize URL, and saves the token with its granted scopes. The
+  app's redirect URI must match, so register one and pass its --port.
+  auto (default) picks secret-service, then pass, then file when
+  TODI_CREDENTIALS_PASSPHRASE is set, then plaintext.
+  migrate moves every stored token to the target store.
+  login checks the token with the API before saving it and rejects it if
+  the API does (exit 3).
+  status reports token source (TODOIST_TOKEN, config or profile) and store,
+  then checks the token and shows the user. It exits 3 when the token is
+  missing or rejected, and non-zero when it cannot be checked.
+  logout and status act on the selected profile (--profile, TODI_PROFILE,
+  or the one chosen with todi profile use).
+`); err != nil {
+		return
+	}
+}
+
+func printProfileUsage(out io.Writer) {
+	if _, err := fmt.Fprint(out, `todi profile - named profile commands
+
+USAGE:
+  todi profile list
+  todi profile use <name>
+  todi profile remove <name> [--force]
+
+FLAGS (remove):
+  --force                  Skip confirmation
 
 NOTES:
-  login prompts for a token (TTY required).
-  status reports token source (TODOIST_TOKEN or config).
+  Each profile has its own token, api_base, default_project and
+  default_labels; the cache and offline queue are kept per account.
+  Other config keys are shared. Create a profile with
+  todi auth login --profile <name>.
+  The profile comes from --profile, then TODI_PROFILE, then profile use.
+  A config without profiles has one account, listed as "default"; adding
+  the first profile moves it into a profile named default.
 `); err != nil {
 		return
 	}
@@ -175,6 +324,8 @@ FLAGS (list):
   --limit <n>              Max comments per page (1-200)
   --cursor <cursor>        Pagination cursor
   --all                    Fetch all pages
+  --fields <a,b>           Columns to show (JSON keys, plus project/section names)
+  --sort <a,-b>            Sort by fields (-field for descending)
 
 FLAGS (add):
   --task <title>           Task title (exact match)
@@ -222,6 +373,8 @@ FLAGS (list):
   --annotate-notes         Include note info in extra_data
   --annotate-parents       Include parent info in extra_data
   --all                    Fetch all pages
+  --fields <a,b>           Columns to show (JSON keys, plus project/section names)
+  --sort <a,-b>            Sort by fields (-field for descending)
 
 EXAMPLES:
   todi activity list
@@ -246,6 +399,8 @@ FLAGS (list):
   --limit <n>              Max labels per page (1-200)
   --cursor <cursor>        Pagination cursor
   --all                    Fetch all pages
+  --fields <a,b>           Columns to show (JSON keys, plus project/section names)
+  --sort <a,-b>            Sort by fields (-field for descending)
 
 FLAGS (get/update/delete):
   --id                     Treat argument as label ID
@@ -315,6 +470,8 @@ FLAGS (list):
   --limit <n>              Max sections per page (1-200)
   --cursor <cursor>        Pagination cursor
   --all                    Fetch all pages
+  --fields <a,b>           Columns to show (JSON keys, plus project/section names)
+  --sort <a,-b>            Sort by fields (-field for descending)
 
 FLAGS (get/update/delete):
   --id                     Treat argument as section ID
@@ -358,6 +515,12 @@ FLAGS (list):
   --limit <n>              Max projects per page (1-200)
   --cursor <cursor>        Pagination cursor
   --all                    Fetch all pages
+  --fields <a,b>           Columns to show (JSON keys, plus project/section names)
+  --sort <a,-b>            Sort by fields (-field for descending)
+  --tree                   Indent child projects under parents (fetches all)
+  --favorite               Only favorite projects
+  --shared                 Only shared projects
+  --inbox                  Only the Inbox project
 
 FLAGS (get/update/archive/unarchive/delete):
   --id                     Treat argument as project ID
@@ -384,9 +547,14 @@ EXAMPLES:
   todi project add "Docs" --favorite
   todi project update "Docs" --view board
   todi project archive "Docs"
+  todi project list --tree
 
 NOTES:
   <project> accepts exact project title unless --id is set.
+  Filters apply to the fetched page; add --all to filter every project.
+  --plain project columns: id, name, parent_id, child_order, color,
+  view_style, is_favorite, is_shared, is_archived, inbox_project
+  (--tree appends depth).
 `); err != nil {
 		return
 	}
@@ -405,6 +573,217 @@ OUTPUT:
 	}
 }
 
+func printCacheUsage(out io.Writer) {
+	if _, err := fmt.Fprint(out, `todi cache - local cache commands
+
+USAGE:
+  todi cache status
+  todi cache clear
+
+FLAGS (status/clear):
+  --all                    Every cached account, not just the current one
+
+NOTES:
+  Project, section, label and task listings used for name resolution and
+  list --all are cached per account and API base under the user cache dir.
+  Entries expire after cache_ttl (default 5m) and are dropped after any write.
+  Use --no-cache to bypass the cache or --refresh to refetch and rewrite it.
+`); err != nil {
+		return
+	}
+}
+
+func printSyncUsage(out io.Writer) {
+	if _, err := fmt.Fprint(out, `todi sync - offline write queue commands
+
+USAGE:
+  todi sync status
+  todi sync push
+  todi sync clear [--force]
+
+FLAGS (clear):
+  --force                  Skip confirmation
+
+EXAMPLES:
+  todi --offline add "Buy milk"
+  todi --offline close "Buy milk"
+  todi sync status
+  todi sync push
+
+NOTES:
+  With --offline, task add/update/close/reopen/delete and comment
+  add/update/delete are appended to a journal next to the config file instead
+  of being sent. Tasks created offline get a temp ID (tmp-...) that later
+  queued writes may reference; names resolve against queued tasks and the
+  local cache regardless of age.
+  push replays the journal in order and rewrites temp IDs to real IDs.
+  Writes whose target no longer exists are dropped and reported as conflicts;
+  writes the API rejects as invalid are dropped and reported as rejected.
+  Any other failure stops the push and keeps the remaining writes queued.
+  push exits 1 when any write was dropped.
+`); err != nil {
+		return
+	}
+}
+
+func printAgendaUsage(out io.Writer) {
+	if _, err := fmt.Fprint(out, `todi today|upcoming|overdue - agenda views
+
+USAGE:
+  todi today
+  todi upcoming [--days <n>]
+  todi overdue
+
+FLAGS (upcoming):
+  --days <n>               Days to show, starting today (default 7)
+
+EXAMPLES:
+  todi today
+  todi upcoming --days 14
+  todi overdue --plain
+
+NOTES:
+  Days follow the timezone in your Todoist settings.
+  Tasks are grouped by due day, sorted by priority and then due time.
+  Days before today are marked overdue; today and upcoming include them.
+  --plain rows are task columns plus trailing day and overdue columns.
+  Without a network, cached tasks are shown if a cache exists.
+`); err != nil {
+		return
+	}
+}
+
+func printFilterUsage(out io.Writer) {
+	if _, err := fmt.Fprint(out, `todi filter - saved filter commands
+
+USAGE:
+  todi filter list
+  todi filter get <filter>
+  todi filter add <name>
+  todi filter update <filter>
+  todi filter delete <filter>
+  todi filter run <filter>
+
+FLAGS (list):
+  --favorite               Only favorite filters
+
+FLAGS (get/update/delete/run):
+  --id                     Treat argument as filter ID
+
+FLAGS (add/update):
+  --query <query>          Filter query (required for add)
+  --color <name>           Filter color
+  --favorite               Mark as favorite
+  --order <n>              Position in the filter list
+
+FLAGS (update):
+  --name <name>            New name
+  --unfavorite             Remove favorite
+
+FLAGS (run):
+  --lang <code>            Query language code
+  --limit <n>              Max tasks per page (1-200)
+  --cursor <cursor>        Pagination cursor
+  --all                    Fetch all pages
+
+FLAGS (delete):
+  --force                  Skip confirmation
+
+EXAMPLES:
+  todi filter list
+  todi filter add "Urgent work" --query "#Work & p1" --favorite
+  todi filter update "Urgent work" --query "#Work & (p1 | p2)"
+  todi filter run "Urgent work" --all
+
+NOTES:
+  <filter> accepts exact filter name unless --id is set.
+  run lists matching tasks with the task printers.
+`); err != nil {
+		return
+	}
+}
+
+func printReminderUsage(out io.Writer) {
+	if _, err := fmt.Fprint(out, `todi reminder - reminder commands
+
+USAGE:
+  todi reminder list
+  todi reminder add
+  todi reminder update <id>
+  todi reminder delete <id>
+
+FLAGS (list/add):
+  --task <title>           Task title (exact match)
+  --task-id <id>           Task ID
+
+FLAGS (add/update):
+  --before <n|dur>         Relative: minutes before due (e.g. 30 or 1h)
+  --at <datetime|text>     Absolute: datetime (RFC3339, YYYY-MM-DDTHH:MM) or due string
+  --location <name>        Location: place name
+  --lat <lat>              Location latitude
+  --long <long>            Location longitude
+  --trigger <when>         Location trigger (enter|leave, default enter)
+  --radius <meters>        Location radius
+  --notify <uid>           User ID to notify
+
+FLAGS (delete):
+  --force                  Skip confirmation
+
+EXAMPLES:
+  todi reminder list --task "Write docs"
+  todi reminder add --task "Write docs" --before 30
+  todi reminder add --task-id 123 --at "2026-01-10T09:00"
+  todi reminder add --task "Buy milk" --location Store --lat 52.52 --long 13.40 --radius 100
+  todi reminder update 456 --before 1h
+  todi reminder update 789 --trigger leave
+  todi reminder delete 456 --force
+
+NOTES:
+  add requires a task and exactly one reminder type. Relative reminders need
+  a task with a due time. Datetimes without an offset use the task timezone.
+  update can change one location field of a location reminder (e.g.
+  --radius 200); turning another reminder into a location one needs
+  --location, --lat and --long.
+`); err != nil {
+		return
+	}
+}
+
+func printCompletedUsage(out io.Writer) {
+	if _, err := fmt.Fprint(out, `todi completed - completed task history
+
+USAGE:
+  todi completed list
+
+FLAGS (list):
+  --since <date>           Start of range (YYYY-MM-DD or RFC3339)
+  --until <date>           End of range (YYYY-MM-DD or RFC3339, default now)
+  --project <title>        Project title (exact match)
+  --project-id <id>        Project ID
+  --section <name>         Section name (exact match)
+  --section-id <id>        Section ID
+  --parent <title>         Parent task title (exact match)
+  --parent-id <id>         Parent task ID
+  --limit <n>              Max tasks per page (1-200)
+  --cursor <cursor>        Pagination cursor
+  --all                    Fetch all pages
+
+EXAMPLES:
+  todi completed list
+  todi completed list --since 2026-01-05 --until 2026-01-11 --all
+  todi completed list --project "Work" --plain
+
+NOTES:
+  The range defaults to the 7 days before --until. Bare dates are local and
+  --until includes the whole day. The API accepts ranges up to 3 months;
+  longer ones exit 2 before any request.
+  Use --project or --project-id to scope --section name lookups.
+  --parent matches active tasks first, then tasks completed in the range.
+`); err != nil {
+		return
+	}
+}
+
 func printConfigUsage(out io.Writer) {
 	if _, err := fmt.Fprint(out, `todi config - config commands
 
@@ -412,17 +791,38 @@ USAGE:
   todi config get <key>
   todi config set <key> <value>
   todi config path
-  todi config view
+  todi config view [--effective]
 
 KEYS:
   token              Stored auth token (set via auth login)
   api_base           API base URL override
-  default_project    Stored default project name
-  default_labels     Stored default labels (comma-separated)
+  default_project    Default project title for add and quick
+  default_labels     Default labels for add and quick (comma-separated)
   label_cli          Add label 'cli' to created tasks
+  max_retries        Max API retries on 429/5xx (default 3)
+  retry_max_wait     Max wait between retries (e.g. 30s)
+  cache_ttl          Cache lifetime (default 5m, 0 disables)
+  color              Human output color: auto (default), always or never
+  auth_base          OAuth server for auth login --oauth
+  oauth_client_id    OAuth client ID for auth login --oauth
+  oauth_client_secret
+                     OAuth client secret for auth login --oauth
+  oauth_scopes       OAuth scopes, comma-separated
+  credential_store   Token store: auto (default), secret-service, pass, file
+                     or plaintext (see todi auth)
+  format.<name>      Named --format template, used as --format @name
+                     (an empty value removes it)
+  match.<resource>   Name lookup modes for task, project, section, label or
+                     filter, tried in order: exact (default), case-insensitive,
+                     prefix, substring, fuzzy, id-prefix (an empty value resets)
 
 NOTES:
   token cannot be set via config set.
+  token, api_base, default_project and default_labels belong to the selected
+  profile (see todi profile).
+  Defaults come from flags, then TODI_DEFAULT_PROJECT/TODI_DEFAULT_LABELS,
+  then the nearest .todi.json (current directory or a parent), then this config.
+  view --effective shows each value in effect and where it came from.
 `); err != nil {
 		return
 	}
@@ -18,9 +18,9 @@ func runUser(ctx context.Context, state *state, args []string) int {
 		printUserUsage(state.Out)
 		return 0
 	default:
-		writeLine(state.Err, "error: unknown user command:", args[0])
+		code := reportError(state, usageErrorf("unknown user command: %s", args[0]))
 		printUserUsage(state.Err)
-		return 2
+		return code
 	}
 }
 
@@ -31,32 +31,27 @@ func runUserInfo(ctx context.Context, state *state, args []string) int {
 	fs.BoolVar(&help, "help", false, "Show help")
 	fs.BoolVar(&help, "h", false, "Show help")
 	if err := fs.Parse(args); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 2
+		return reportError(state, usageError{err})
 	}
 	if help {
 		printUserUsage(state.Out)
 		return 0
 	}
 	if len(fs.Args()) > 0 {
-		writeLine(state.Err, "error: unexpected arguments")
-		return 2
+		return reportError(state, usageErrorf("unexpected arguments"))
 	}
 
 	client, err := state.client()
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 
 	user, err := client.GetUserInfo(ctx)
 	if err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	if err := printUser(state.Out, user, state.Mode); err != nil {
-		writeLine(state.Err, "error:", err)
-		return 1
+		return reportError(state, err)
 	}
 	return 0
 }
@@ -0,0 +1,454 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+// listView holds the --fields and --sort flags shared by list commands.
+type listView struct {
+	Fields string
+	Sort   string
+}
+
+// viewKind describes a listed resource: its JSON fields and default columns.
+type viewKind struct {
+	Type     reflect.Type
+	Defaults []string
+}
+
+var (
+	taskView     = viewKind{reflect.TypeOf(todi.Task{}), []string{"id", "content", "due", "priority", "deadline", "labels"}}
+	projectView  = viewKind{reflect.TypeOf(todi.Project{}), []string{"id", "name", "color", "view_style", "is_favorite"}}
+	sectionView  = viewKind{reflect.TypeOf(todi.Section{}), []string{"id", "name", "project", "section_order"}}
+	labelView    = viewKind{reflect.TypeOf(todi.Label{}), []string{"id", "name", "color", "order", "is_favorite"}}
+	commentView  = viewKind{reflect.TypeOf(todi.Comment{}), []string{"id", "content", "posted_at"}}
+	activityView = viewKind{reflect.TypeOf(todi.Activity{}), []string{"id", "event_type", "object_type", "object_id", "event_date"}}
+)
+
+// Name fields resolved from the ID field of the same record.
+var viewNameFields = map[string]string{
+	"project": "project_id",
+	"section": "section_id",
+}
+
+func (v *listView) register(fs *flag.FlagSet) {
+	fs.StringVar(&v.Fields, "fields", "", "Columns to show (comma-separated JSON keys)")
+	fs.StringVar(&v.Sort, "sort", "", "Sort keys (comma-separated, -key for descending)")
+}
+
+func (v listView) active() bool {
+	return v.Fields != "" || v.Sort != ""
+}
+
+// sortKey is one --sort entry.
+type sortKey struct {
+	Field string
+	Desc  bool
+}
+
+// parse validates the flags against kind and returns the columns and sort keys.
+func (v listView) parse(kind viewKind) ([]string, []sortKey, error) {
+	known := kind.fields()
+	fields := kind.Defaults
+	if v.Fields != "" {
+		fields = splitList(v.Fields)
+		if len(fields) == 0 {
+			return nil, nil, fmt.Errorf("--fields is empty")
+		}
+	}
+	for _, field := range fields {
+		if !known[field] {
+			return nil, nil, fmt.Errorf("unknown field: %s", field)
+		}
+	}
+	var keys []sortKey
+	for _, item := range splitList(v.Sort) {
+		key := sortKey{Field: item}
+		if name, ok := strings.CutPrefix(item, "-"); ok {
+			key = sortKey{Field: name, Desc: true}
+		}
+		if !known[key.Field] {
+			return nil, nil, fmt.Errorf("unknown sort field: %s", key.Field)
+		}
+		keys = append(keys, key)
+	}
+	return fields, keys, nil
+}
+
+// keys returns the JSON keys of the kind in declaration order, followed by
+// its name fields.
+func (k viewKind) keys() []string {
+	keys := k.tagKeys()
+	known := k.fields()
+	for _, name := range []string{"project", "section"} {
+		if known[name] {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// tagKeys returns the JSON keys of the kind in declaration order.
+func (k viewKind) tagKeys() []string {
+	var keys []string
+	for i := 0; i < k.Type.NumField(); i++ {
+		name, _, _ := strings.Cut(k.Type.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			keys = append(keys, name)
+		}
+	}
+	return keys
+}
+
+// fields returns the JSON keys of the kind plus the name fields it supports.
+func (k viewKind) fields() map[string]bool {
+	known := map[string]bool{}
+	for i := 0; i < k.Type.NumField(); i++ {
+		name, _, _ := strings.Cut(k.Type.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			known[name] = true
+		}
+	}
+	for name, idField := range viewNameFields {
+		if known[idField] {
+			known[name] = true
+		}
+	}
+	return known
+}
+
+// fillZero sets boolean and numeric fields that were omitted as empty, so
+// false and 0 show up in columns instead of blanks.
+func (k viewKind) fillZero(records []map[string]any) {
+	for i := 0; i < k.Type.NumField(); i++ {
+		field := k.Type.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		var zero any
+		switch field.Type.Kind() {
+		case reflect.Bool:
+			zero = false
+		case reflect.Int, reflect.Int64:
+			zero = json.Number("0")
+		default:
+			continue
+		}
+		for _, record := range records {
+			if _, ok := record[name]; !ok {
+				record[name] = zero
+			}
+		}
+	}
+}
+
+// print renders items, a slice of API models, with the selected columns and
+// order. next is included in JSON output when the listing is paged.
+func (v listView) print(ctx context.Context, state *state, client *todi.Client, kind viewKind, items any, next *string) error {
+	fields, keys, err := v.parse(kind)
+	if err != nil {
+		return usageError{err}
+	}
+	records, err := viewRecords(items)
+	if err != nil {
+		return err
+	}
+	kind.fillZero(records)
+	if err := resolveViewNames(ctx, client, records, fields, keys); err != nil {
+		return err
+	}
+	sortRecords(records, keys)
+
+	switch state.Mode {
+	case modeJSON:
+		keys := kind.keys()
+		if v.Fields != "" {
+			keys = fields
+		}
+		setColumns(state.Out, viewColumns(keys, records, v.Fields != ""))
+		results := make([]*jsonObject, 0, len(records))
+		for _, record := range records {
+			obj := &jsonObject{Values: map[string]any{}}
+			for _, key := range keys {
+				if value, ok := record[key]; ok || v.Fields != "" {
+					obj.Set(key, value)
+				}
+			}
+			results = append(results, obj)
+		}
+		payload := map[string]any{"results": results}
+		if next != nil {
+			payload["next_cursor"] = *next
+		}
+		return printJSON(state.Out, payload)
+	case modePlain:
+		for _, record := range records {
+			row := make([]string, 0, len(fields))
+			for _, field := range fields {
+				row = append(row, viewValue(record, field, false))
+			}
+			if _, err := fmt.Fprintln(state.Out, strings.Join(row, "\t")); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		return printViewTable(state.Out, records, fields)
+	}
+}
+
+// viewColumns returns the table columns for keys: all of them for --fields,
+// otherwise name fields only when they were resolved.
+func viewColumns(keys []string, records []map[string]any, explicit bool) []string {
+	if explicit {
+		return keys
+	}
+	columns := make([]string, 0, len(keys))
+	for _, key := range keys {
+		if _, name := viewNameFields[key]; name && !slices.ContainsFunc(records, func(r map[string]any) bool {
+			_, ok := r[key]
+			return ok
+		}) {
+			continue
+		}
+		columns = append(columns, key)
+	}
+	return columns
+}
+
+func printViewTable(out io.Writer, records []map[string]any, fields []string) error {
+	st := styleOf(out)
+	w := newTable(out)
+	header := make([]string, 0, len(fields))
+	for _, field := range fields {
+		header = append(header, strings.ToUpper(field))
+	}
+	if _, err := fmt.Fprintln(w, strings.Join(header, "\t")); err != nil {
+		return err
+	}
+	for _, record := range records {
+		row := make([]string, 0, len(fields))
+		for _, field := range fields {
+			row = append(row, styleViewValue(st, record, field))
+		}
+		if _, err := fmt.Fprintln(w, strings.Join(row, "\t")); err != nil {
+			return err
+		}
+	}
+	return w.Flush()
+}
+
+// viewRecords converts API models to their JSON objects.
+func viewRecords(items any) ([]map[string]any, error) {
+	if tasks, ok := items.([]todi.Task); ok {
+		items = withTaskURLs(tasks)
+	}
+	data, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var records []map[string]any
+	if err := dec.Decode(&records); err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// resolveViewNames fills project and section name fields, fetching each
+// listing once (and through the cache) only when a name is needed.
+func resolveViewNames(ctx context.Context, client *todi.Client, records []map[string]any, fields []string, keys []sortKey) error {
+	needed := map[string]bool{}
+	for _, field := range fields {
+		needed[field] = true
+	}
+	for _, key := range keys {
+		needed[key.Field] = true
+	}
+	names := map[string]map[string]string{}
+	if needed["project"] {
+		projects, err := client.ListProjectsAll(ctx)
+		if err != nil {
+			return err
+		}
+		names["project"] = make(map[string]string, len(projects))
+		for _, project := range projects {
+			names["project"][project.ID] = project.Name
+		}
+	}
+	if needed["section"] {
+		sections, err := client.ListSectionsAll(ctx, map[string]string{})
+		if err != nil {
+			return err
+		}
+		names["section"] = make(map[string]string, len(sections))
+		for _, section := range sections {
+			names["section"][section.ID] = section.Name
+		}
+	}
+	for field, byID := range names {
+		idField := viewNameFields[field]
+		for _, record := range records {
+			id, _ := record[idField].(string)
+			if id == "" {
+				continue
+			}
+			name := byID[id]
+			if name == "" {
+				name = id
+			}
+			record[field] = name
+		}
+	}
+	return nil
+}
+
+func sortRecords(records []map[string]any, keys []sortKey) {
+	if len(keys) == 0 {
+		return
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, key := range keys {
+			c := compareViewValues(records[i], records[j], key.Field)
+			if c == 0 {
+				continue
+			}
+			// Empty values sort last in either direction.
+			if viewValue(records[i], key.Field, false) == "" || viewValue(records[j], key.Field, false) == "" {
+				return c < 0
+			}
+			if key.Desc {
+				return c > 0
+			}
+			return c < 0
+		}
+		return false
+	})
+}
+
+// compareViewValues orders numbers numerically and everything else by its
+// display text. An empty value compares greater than any other.
+func compareViewValues(a, b map[string]any, field string) int {
+	x, y := viewValue(a, field, false), viewValue(b, field, false)
+	switch {
+	case x == y:
+		return 0
+	case x == "":
+		return 1
+	case y == "":
+		return -1
+	}
+	if xn, ok := a[field].(json.Number); ok {
+		if yn, ok := b[field].(json.Number); ok {
+			xf, xerr := xn.Float64()
+			yf, yerr := yn.Float64()
+			if xerr == nil && yerr == nil {
+				switch {
+				case xf < yf:
+					return -1
+				case xf > yf:
+					return 1
+				}
+				return 0
+			}
+		}
+	}
+	return strings.Compare(x, y)
+}
+
+// viewValue renders one field of a record as text. human applies the same
+// display rules as the default tables, such as p1 for API priority 4.
+func viewValue(record map[string]any, field string, human bool) string {
+	value := record[field]
+	if field == "priority" && human {
+		return formatPriority(value)
+	}
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case json.Number:
+		return v.String()
+	case bool:
+		return strconv.FormatBool(v)
+	case []any:
+		return formatJoin(",", v)
+	case map[string]any:
+		switch field {
+		case "due":
+			return formatDue(v)
+		case "deadline":
+			date, _ := v["date"].(string)
+			return date
+		case "duration":
+			return strings.TrimSpace(fmt.Sprint(v["amount"], " ", v["unit"]))
+		}
+		text, err := formatJSON(v)
+		if err != nil {
+			return ""
+		}
+		return text
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// styleViewValue renders a human table cell, styled as the default tables
+// style the same field.
+func styleViewValue(st style, record map[string]any, field string) string {
+	text := viewValue(record, field, true)
+	if !st.on {
+		return text
+	}
+	switch field {
+	case "id":
+		return st.id(text)
+	case "name":
+		color, _ := record["color"].(string)
+		return st.color(color, text)
+	case "priority":
+		n, err := strconv.Atoi(viewValue(record, field, false))
+		if err != nil {
+			return text
+		}
+		return st.priority(n)
+	case "due":
+		obj, _ := record[field].(map[string]any)
+		date, _ := obj["date"].(string)
+		datetime, _ := obj["datetime"].(string)
+		if st.overdue(&todi.Due{Date: date, Datetime: datetime}) {
+			return st.paint(ansiRed, text)
+		}
+	case "labels":
+		items, _ := record[field].([]any)
+		names := make([]string, 0, len(items))
+		for _, item := range items {
+			names = append(names, fmt.Sprint(item))
+		}
+		return st.labelList(names, ",")
+	}
+	return text
+}
+
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
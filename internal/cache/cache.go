@@ -0,0 +1,316 @@
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultTTL is how long cached listings are served before refetching.
+const DefaultTTL = 5 * time.Minute
+
+const (
+	metaFile   = "meta.json"
+	entryExt   = ".json"
+	entryGlob  = "*" + entryExt
+	dirPerm    = 0o700
+	filePerm   = 0o600
+	keyHashLen = 16
+	// syncKey holds the incremental sync state. It is served regardless
+	// of age and kept by Invalidate, since each sync brings it up to date.
+	syncKey = "sync"
+)
+
+// Store is a file-backed cache for one account on one API base.
+type Store struct {
+	Dir     string
+	APIBase string
+	TTL     time.Duration
+	// Refresh skips reads so every listing is refetched and rewritten.
+	Refresh bool
+	// Stale serves entries regardless of age, for use without a network.
+	Stale bool
+}
+
+// Entry describes one cached listing.
+type Entry struct {
+	Key       string    `json:"key"`
+	FetchedAt time.Time `json:"fetched_at"`
+	Size      int64     `json:"size"`
+	Fresh     bool      `json:"fresh"`
+}
+
+// Account describes the cache directory of one account.
+type Account struct {
+	Dir     string  `json:"dir"`
+	APIBase string  `json:"api_base"`
+	Entries []Entry `json:"entries"`
+}
+
+type record struct {
+	Key       string          `json:"key"`
+	FetchedAt time.Time       `json:"fetched_at"`
+	Data      json.RawMessage `json:"data"`
+}
+
+type meta struct {
+	APIBase string `json:"api_base"`
+}
+
+// DefaultRoot returns the default cache root directory.
+func DefaultRoot() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("cache dir: %w", err)
+	}
+	return filepath.Join(dir, "todi"), nil
+}
+
+// AccountKey derives a stable directory name for a token on an API base.
+func AccountKey(apiBase, token string) string {
+	sum := sha256.Sum256([]byte(strings.TrimRight(apiBase, "/") + "\n" + token))
+	return hex.EncodeToString(sum[:])[:keyHashLen]
+}
+
+// Open returns the store for an account under root.
+func Open(root, apiBase, token string, ttl time.Duration) *Store {
+	return &Store{
+		Dir:     filepath.Join(root, AccountKey(apiBase, token)),
+		APIBase: apiBase,
+		TTL:     ttl,
+	}
+}
+
+// Get decodes a fresh entry into out.
+func (s *Store) Get(key string, out any) bool {
+	if s.Refresh {
+		return false
+	}
+	rec, err := s.read(key)
+	if err != nil || !s.fresh(rec.FetchedAt) {
+		return false
+	}
+	return json.Unmarshal(rec.Data, out) == nil
+}
+
+// Put stores value under key.
+func (s *Store) Put(key string, value any) {
+	if err := s.write(key, value); err != nil {
+		return
+	}
+}
+
+// GetSync decodes the saved sync state into out.
+func (s *Store) GetSync(out any) bool {
+	if s.Refresh {
+		return false
+	}
+	rec, err := s.read(syncKey)
+	if err != nil {
+		return false
+	}
+	return json.Unmarshal(rec.Data, out) == nil
+}
+
+// PutSync saves the sync state.
+func (s *Store) PutSync(value any) {
+	s.Put(syncKey, value)
+}
+
+// Invalidate removes every listing for the account, keeping the sync state.
+func (s *Store) Invalidate() {
+	if err := s.remove(s.path(syncKey)); err != nil {
+		return
+	}
+}
+
+// Clear removes every entry for the account and reports failures.
+func (s *Store) Clear() error {
+	return s.remove("")
+}
+
+// remove deletes the account's entries except keep.
+func (s *Store) remove(keep string) error {
+	paths, err := filepath.Glob(filepath.Join(s.Dir, entryGlob))
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		if filepath.Base(path) == metaFile || path == keep {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove cache entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// Entries lists the cached listings for the account, oldest key first.
+func (s *Store) Entries() ([]Entry, error) {
+	return readEntries(s.Dir, s.TTL, time.Now())
+}
+
+// Accounts lists every account directory under root.
+func Accounts(root string, ttl time.Duration) ([]Account, error) {
+	dirs, err := os.ReadDir(root)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read cache dir: %w", err)
+	}
+	now := time.Now()
+	accounts := make([]Account, 0, len(dirs))
+	for _, dir := range dirs {
+		if !dir.IsDir() {
+			continue
+		}
+		path := filepath.Join(root, dir.Name())
+		account := Account{Dir: path}
+		if data, err := os.ReadFile(filepath.Join(path, metaFile)); err == nil {
+			var m meta
+			if json.Unmarshal(data, &m) == nil {
+				account.APIBase = m.APIBase
+			}
+		}
+		entries, err := readEntries(path, ttl, now)
+		if err != nil {
+			return nil, err
+		}
+		account.Entries = entries
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+// ClearAll removes the whole cache root.
+func ClearAll(root string) error {
+	if err := os.RemoveAll(root); err != nil {
+		return fmt.Errorf("remove cache dir: %w", err)
+	}
+	return nil
+}
+
+func readEntries(dir string, ttl time.Duration, now time.Time) ([]Entry, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, entryGlob))
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(paths))
+	for _, path := range paths {
+		if filepath.Base(path) == metaFile {
+			continue
+		}
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+		rec, err := readRecord(path)
+		if err != nil {
+			continue
+		}
+		entries = append(entries, Entry{
+			Key:       rec.Key,
+			FetchedAt: rec.FetchedAt,
+			Size:      info.Size(),
+			Fresh:     ttl > 0 && now.Sub(rec.FetchedAt) < ttl,
+		})
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Key < entries[j].Key })
+	return entries, nil
+}
+
+func (s *Store) fresh(fetchedAt time.Time) bool {
+	if s.Stale {
+		return true
+	}
+	return s.TTL > 0 && time.Since(fetchedAt) < s.TTL
+}
+
+func (s *Store) path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(s.Dir, hex.EncodeToString(sum[:])[:keyHashLen]+entryExt)
+}
+
+func (s *Store) read(key string) (record, error) {
+	rec, err := readRecord(s.path(key))
+	if err != nil {
+		return record{}, err
+	}
+	if rec.Key != key {
+		return record{}, errors.New("cache key mismatch")
+	}
+	return rec, nil
+}
+
+func readRecord(path string) (record, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return record{}, err
+	}
+	var rec record
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return record{}, err
+	}
+	return rec, nil
+}
+
+func (s *Store) write(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	rec, err := json.Marshal(record{Key: key, FetchedAt: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, dirPerm); err != nil {
+		return err
+	}
+	if err := s.writeMeta(); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.path(key), rec)
+}
+
+func (s *Store) writeMeta() error {
+	path := filepath.Join(s.Dir, metaFile)
+	if _, err := os.Stat(path); err == nil {
+		return nil
+	}
+	data, err := json.Marshal(meta{APIBase: s.APIBase})
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(path, data)
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	name := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	if err := os.Chmod(name, filePerm); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	return os.Rename(name, path)
+}
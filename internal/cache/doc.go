@@ -0,0 +1,2 @@
+// Package cache stores Todoist listings on disk between CLI runs.
+package cache
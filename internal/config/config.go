@@ -8,14 +8,50 @@ import (
 	"path/filepath"
 )
 
+// Profile holds the account settings kept per named profile.
+type Profile struct {
+	// Token is a token kept in plaintext; TokenRef points to one kept in a
+	// CredentialStore instead, as "<store>:<id>".
+	Token    string `json:"token,omitempty"`
+	TokenRef string `json:"token_ref,omitempty"`
+	// Scopes lists the scopes granted to a token from OAuth login.
+	Scopes  string `json:"token_scopes,omitempty"`
+	APIBase string `json:"api_base,omitempty"`
+	Project string `json:"default_project,omitempty"`
+	Labels  string `json:"default_labels,omitempty"`
+}
+
+// DefaultProfile names the account of a config without profiles, and the
+// profile it is migrated to.
+const DefaultProfile = "default"
+
 // Config stores CLI configuration values.
 type Config struct {
-	Token    string `json:"token,omitempty"`
-	APIBase  string `json:"api_base,omitempty"`
-	Project  string `json:"default_project,omitempty"`
-	Labels   string `json:"default_labels,omitempty"`
-	LabelCLI bool   `json:"label_cli,omitempty"`
-	Filename string `json:"-"`
+	// Profile is the top-level account, used when no profile is selected.
+	Profile
+	// Current is the profile used when neither --profile nor TODI_PROFILE
+	// is set.
+	Current      string              `json:"profile,omitempty"`
+	Profiles     map[string]*Profile `json:"profiles,omitempty"`
+	LabelCLI     bool                `json:"label_cli,omitempty"`
+	MaxRetries   *int                `json:"max_retries,omitempty"`
+	RetryMaxWait string              `json:"retry_max_wait,omitempty"`
+	CacheTTL     string              `json:"cache_ttl,omitempty"`
+	// CredentialStore is where auth login saves tokens; see ResolveStore.
+	CredentialStore string `json:"credential_store,omitempty"`
+	// AuthBase and the OAuth fields configure todi auth login --oauth.
+	AuthBase          string `json:"auth_base,omitempty"`
+	OAuthClientID     string `json:"oauth_client_id,omitempty"`
+	OAuthClientSecret string `json:"oauth_client_secret,omitempty"`
+	OAuthScopes       string `json:"oauth_scopes,omitempty"`
+	// Color is auto, always or never; empty means auto.
+	Color string `json:"color,omitempty"`
+	// Match holds comma-separated name lookup modes per resource, set as
+	// match.<resource>.
+	Match map[string]string `json:"match,omitempty"`
+	// Formats holds named --format templates, used as --format @name.
+	Formats  map[string]string `json:"formats,omitempty"`
+	Filename string            `json:"-"`
 }
 
 // DefaultPath returns the default config file path.
@@ -47,6 +83,95 @@ func Load(path string) (*Config, error) {
 	return &cfg, nil
 }
 
+// Account returns the settings of the named profile, or of the top-level
+// account for "". Until profiles are added, DefaultProfile also refers to
+// the top-level account. It returns nil for an unknown profile.
+func (c *Config) Account(name string) *Profile {
+	if name == "" || (name == DefaultProfile && len(c.Profiles) == 0) {
+		return &c.Profile
+	}
+	return c.Profiles[name]
+}
+
+// AddProfile returns the named profile, creating it if needed. Adding the
+// first profile migrates a top-level account into DefaultProfile and makes
+// it current, so existing logins keep working.
+func (c *Config) AddProfile(name string) *Profile {
+	if profile, ok := c.Profiles[name]; ok {
+		return profile
+	}
+	if c.Profiles == nil {
+		c.Profiles = map[string]*Profile{}
+		if c.Profile != (Profile{}) {
+			legacy := c.Profile
+			c.Profiles[DefaultProfile] = &legacy
+			c.Profile = Profile{}
+			if c.Current == "" {
+				c.Current = DefaultProfile
+			}
+		}
+		if profile, ok := c.Profiles[name]; ok {
+			return profile
+		}
+	}
+	profile := &Profile{}
+	c.Profiles[name] = profile
+	if c.Current == "" {
+		c.Current = name
+	}
+	return profile
+}
+
+// RemoveProfile deletes the named profile, reporting whether it existed.
+// Removing the current profile falls back to DefaultProfile when it
+// remains, and otherwise unsets Current.
+func (c *Config) RemoveProfile(name string) bool {
+	if name == DefaultProfile && len(c.Profiles) == 0 {
+		if c.Profile == (Profile{}) {
+			return false
+		}
+		c.Profile = Profile{}
+	} else {
+		if _, ok := c.Profiles[name]; !ok {
+			return false
+		}
+		delete(c.Profiles, name)
+		if len(c.Profiles) == 0 {
+			c.Profiles = nil
+		}
+	}
+	if c.Current == name {
+		c.Current = ""
+		if _, ok := c.Profiles[DefaultProfile]; ok {
+			c.Current = DefaultProfile
+		}
+	}
+	return true
+}
+
+// LocalName is the per-directory config file.
+const LocalName = ".todi.json"
+
+// FindLocal loads the LocalName file closest to dir, looking in dir and then
+// each parent. It returns nil when there is none.
+func FindLocal(dir string) (*Config, error) {
+	for {
+		path := filepath.Join(dir, LocalName)
+		_, err := os.Stat(path)
+		if err == nil {
+			return Load(path)
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("read config: %w", err)
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return nil, nil
+		}
+		dir = parent
+	}
+}
+
 // Save writes config values to the provided path.
 func (c *Config) Save(path string) error {
 	if path == "" {
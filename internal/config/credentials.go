@@ -0,0 +1,164 @@
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Credential store names, as used by the credential_store config key.
+const (
+	StoreAuto          = "auto"
+	StorePlaintext     = "plaintext"
+	StoreFile          = "file"
+	StoreSecretService = "secret-service"
+	StorePass          = "pass"
+)
+
+// ErrNoCredential is returned by a CredentialStore that holds no entry for
+// an ID.
+var ErrNoCredential = errors.New("credential not found")
+
+// CredentialStore keeps tokens outside the config file. The config refers to
+// each entry by store name and a random ID (Profile.TokenRef).
+type CredentialStore interface {
+	Name() string
+	Get(id string) (string, error)
+	Set(id, token string) error
+	Delete(id string) error
+}
+
+// ValidStore reports whether name is a known credential_store value.
+func ValidStore(name string) bool {
+	switch name {
+	case StoreAuto, StorePlaintext, StoreFile, StoreSecretService, StorePass:
+		return true
+	default:
+		return false
+	}
+}
+
+// OpenStore returns the named store. dir is the config directory, which
+// holds the encrypted file store.
+func OpenStore(name, dir string) (CredentialStore, error) {
+	switch name {
+	case StoreFile:
+		return openFileStore(dir)
+	case StoreSecretService, StorePass:
+		store := systemStore(name)
+		if store == nil {
+			return nil, fmt.Errorf("credential store %s is not available", name)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unknown credential store: %s", name)
+	}
+}
+
+// ResolveStore turns a credential_store value into the store for new
+// tokens. auto picks a system keyring when one is usable, then the
+// encrypted file when its passphrase is set, and falls back to plaintext.
+func ResolveStore(name string) string {
+	if name != "" && name != StoreAuto {
+		return name
+	}
+	for _, candidate := range []string{StoreSecretService, StorePass} {
+		if systemStore(candidate) != nil {
+			return candidate
+		}
+	}
+	if filePassphrase() != "" {
+		return StoreFile
+	}
+	return StorePlaintext
+}
+
+// TokenStore names the store holding the account's token, or "" when there
+// is none.
+func (p *Profile) TokenStore() string {
+	if p.Token != "" {
+		return StorePlaintext
+	}
+	name, _, _ := strings.Cut(p.TokenRef, ":")
+	return name
+}
+
+// ReadToken returns the account's token from wherever it is stored.
+func (p *Profile) ReadToken(dir string) (string, error) {
+	if p.Token != "" || p.TokenRef == "" {
+		return p.Token, nil
+	}
+	name, id, _ := strings.Cut(p.TokenRef, ":")
+	store, err := OpenStore(name, dir)
+	if err != nil {
+		return "", err
+	}
+	token, err := store.Get(id)
+	if err != nil {
+		return "", fmt.Errorf("read token from %s: %w", name, err)
+	}
+	return token, nil
+}
+
+// SaveToken puts token in the named store, or in the config for
+// StorePlaintext. It returns the reference to the previous copy, which the
+// caller removes with DeleteToken once the config is saved.
+func (p *Profile) SaveToken(token, name, dir string) (string, error) {
+	previous := p.TokenRef
+	if name == StorePlaintext {
+		p.Token = token
+		p.TokenRef = ""
+		return previous, nil
+	}
+	store, err := OpenStore(name, dir)
+	if err != nil {
+		return "", err
+	}
+	id, err := newCredentialID()
+	if err != nil {
+		return "", err
+	}
+	if err := store.Set(id, token); err != nil {
+		return "", fmt.Errorf("save token to %s: %w", name, err)
+	}
+	p.Token = ""
+	p.TokenRef = name + ":" + id
+	return previous, nil
+}
+
+// ClearToken removes the token from the config and returns the reference to
+// pass to DeleteToken.
+func (p *Profile) ClearToken() string {
+	previous := p.TokenRef
+	p.Token = ""
+	p.TokenRef = ""
+	p.Scopes = ""
+	return previous
+}
+
+// DeleteToken removes a stored token by reference. An empty reference or a
+// missing entry is not an error.
+func DeleteToken(ref, dir string) error {
+	if ref == "" {
+		return nil
+	}
+	name, id, _ := strings.Cut(ref, ":")
+	store, err := OpenStore(name, dir)
+	if err != nil {
+		return err
+	}
+	if err := store.Delete(id); err != nil && !errors.Is(err, ErrNoCredential) {
+		return fmt.Errorf("delete token from %s: %w", name, err)
+	}
+	return nil
+}
+
+func newCredentialID() (string, error) {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
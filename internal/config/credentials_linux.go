@@ -0,0 +1,118 @@
+//go:build linux
+
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// systemStore returns the named keyring backend when its tool is installed
+// and usable, or nil.
+func systemStore(name string) CredentialStore {
+	switch name {
+	case StoreSecretService:
+		if _, err := exec.LookPath("secret-tool"); err != nil || os.Getenv("DBUS_SESSION_BUS_ADDRESS") == "" {
+			return nil
+		}
+		return secretServiceStore{}
+	case StorePass:
+		if _, err := exec.LookPath("pass"); err != nil {
+			return nil
+		}
+		dir := os.Getenv("PASSWORD_STORE_DIR")
+		if dir == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return nil
+			}
+			dir = filepath.Join(home, ".password-store")
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".gpg-id")); err != nil {
+			return nil
+		}
+		return passStore{}
+	default:
+		return nil
+	}
+}
+
+// secretServiceStore uses the Secret Service API (GNOME Keyring, KWallet)
+// through secret-tool.
+type secretServiceStore struct{}
+
+func (secretServiceStore) Name() string { return StoreSecretService }
+
+func (secretServiceStore) Get(id string) (string, error) {
+	out, err := runTool("", "secret-tool", "lookup", "service", "todi", "id", id)
+	if err != nil {
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) && len(out) == 0 {
+			return "", ErrNoCredential
+		}
+		return "", err
+	}
+	return strings.TrimRight(out, "\n"), nil
+}
+
+func (secretServiceStore) Set(id, token string) error {
+	_, err := runTool(token, "secret-tool", "store", "--label", "todi token "+id, "service", "todi", "id", id)
+	return err
+}
+
+func (secretServiceStore) Delete(id string) error {
+	_, err := runTool("", "secret-tool", "clear", "service", "todi", "id", id)
+	return err
+}
+
+// passStore uses the pass password manager, under todi/<id>.
+type passStore struct{}
+
+func (passStore) Name() string { return StorePass }
+
+func (passStore) Get(id string) (string, error) {
+	out, err := runTool("", "pass", "show", "todi/"+id)
+	if err != nil {
+		if strings.Contains(err.Error(), "not in the password store") {
+			return "", ErrNoCredential
+		}
+		return "", err
+	}
+	token, _, _ := strings.Cut(out, "\n")
+	return token, nil
+}
+
+func (passStore) Set(id, token string) error {
+	_, err := runTool(token+"\n", "pass", "insert", "--multiline", "--force", "todi/"+id)
+	return err
+}
+
+func (passStore) Delete(id string) error {
+	_, err := runTool("", "pass", "rm", "--force", "todi/"+id)
+	if err != nil && strings.Contains(err.Error(), "not in the password store") {
+		return ErrNoCredential
+	}
+	return err
+}
+
+// runTool runs a keyring helper, feeding stdin and returning stdout. Errors
+// carry the tool's stderr.
+func runTool(stdin, name string, args ...string) (string, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(stdin)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return stdout.String(), fmt.Errorf("%s: %s: %w", name, msg, err)
+		}
+		return stdout.String(), fmt.Errorf("%s: %w", name, err)
+	}
+	return stdout.String(), nil
+}
@@ -0,0 +1,8 @@
+//go:build !linux
+
+package config
+
+// systemStore returns nil: keyring backends are only wired up on Linux.
+func systemStore(string) CredentialStore {
+	return nil
+}
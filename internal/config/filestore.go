@@ -0,0 +1,229 @@
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// PassphraseEnv holds the passphrase of the encrypted file store. Reading it
+// from the environment keeps the store usable without a desktop session.
+const PassphraseEnv = "TODI_CREDENTIALS_PASSPHRASE"
+
+const (
+	credentialsFile  = "credentials.enc"
+	pbkdf2Iterations = 210000
+	keyLen           = 32
+)
+
+// fileStore keeps tokens in an AES-256-GCM encrypted file next to the
+// config. The key is derived from the passphrase with PBKDF2-HMAC-SHA256.
+type fileStore struct {
+	path       string
+	passphrase string
+}
+
+// sealedFile is the on-disk form of the file store.
+type sealedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+func filePassphrase() string {
+	return os.Getenv(PassphraseEnv)
+}
+
+func openFileStore(dir string) (*fileStore, error) {
+	passphrase := filePassphrase()
+	if passphrase == "" {
+		return nil, fmt.Errorf("credential store file needs %s", PassphraseEnv)
+	}
+	return &fileStore{path: filepath.Join(dir, credentialsFile), passphrase: passphrase}, nil
+}
+
+func (s *fileStore) Name() string { return StoreFile }
+
+func (s *fileStore) Get(id string) (string, error) {
+	entries, _, err := s.load()
+	if err != nil {
+		return "", err
+	}
+	token, ok := entries[id]
+	if !ok {
+		return "", ErrNoCredential
+	}
+	return token, nil
+}
+
+func (s *fileStore) Set(id, token string) error {
+	entries, salt, err := s.load()
+	if err != nil {
+		return err
+	}
+	entries[id] = token
+	return s.save(entries, salt)
+}
+
+func (s *fileStore) Delete(id string) error {
+	entries, salt, err := s.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := entries[id]; !ok {
+		return ErrNoCredential
+	}
+	delete(entries, id)
+	return s.save(entries, salt)
+}
+
+// load decrypts the file. A missing file is an empty store.
+func (s *fileStore) load() (map[string]string, []byte, error) {
+	data, err := os.ReadFile(s.path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return map[string]string{}, nil, nil
+		}
+		return nil, nil, fmt.Errorf("read credentials: %w", err)
+	}
+	var sealed sealedFile
+	if err := json.Unmarshal(data, &sealed); err != nil {
+		return nil, nil, fmt.Errorf("parse credentials: %w", err)
+	}
+	if sealed.Version != 1 || sealed.KDF != "pbkdf2-sha256" {
+		return nil, nil, fmt.Errorf("unsupported credentials file: %s", s.path)
+	}
+	gcm, err := newGCM(s.passphrase, sealed.Salt, sealed.Iterations)
+	if err != nil {
+		return nil, nil, err
+	}
+	plain, err := gcm.Open(nil, sealed.Nonce, sealed.Ciphertext, nil)
+	if err != nil {
+		return nil, nil, fmt.Errorf("decrypt credentials: wrong %s or corrupt file", PassphraseEnv)
+	}
+	entries := map[string]string{}
+	if err := json.Unmarshal(plain, &entries); err != nil {
+		return nil, nil, fmt.Errorf("parse credentials: %w", err)
+	}
+	return entries, sealed.Salt, nil
+}
+
+// save encrypts entries with a fresh nonce, keeping the salt of an existing
+// file.
+func (s *fileStore) save(entries map[string]string, salt []byte) error {
+	if salt == nil {
+		salt = make([]byte, 16)
+		if _, err := rand.Read(salt); err != nil {
+			return err
+		}
+	}
+	gcm, err := newGCM(s.passphrase, salt, pbkdf2Iterations)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	plain, err := json.Marshal(entries)
+	if err != nil {
+		return err
+	}
+	sealed := sealedFile{
+		Version:    1,
+		KDF:        "pbkdf2-sha256",
+		Iterations: pbkdf2Iterations,
+		Salt:       salt,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plain, nil),
+	}
+	data, err := json.MarshalIndent(sealed, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("mkdir config dir: %w", err)
+	}
+	if err := writeFileAtomic(s.path, data); err != nil {
+		return fmt.Errorf("write credentials: %w", err)
+	}
+	return nil
+}
+
+// writeFileAtomic writes data to a synced temp file next to path and
+// renames it into place, so a failed write leaves the old file intact.
+func writeFileAtomic(path string, data []byte) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	name := tmp.Name()
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	if err := os.Chmod(name, 0o600); err != nil {
+		_ = os.Remove(name)
+		return err
+	}
+	return os.Rename(name, path)
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	if iterations <= 0 {
+		return nil, errors.New("invalid credentials file: iterations")
+	}
+	block, err := aes.NewCipher(pbkdf2SHA256([]byte(passphrase), salt, iterations, keyLen))
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// pbkdf2SHA256 implements PBKDF2 (RFC 8018) with HMAC-SHA256, which the
+// standard library of our Go version does not provide.
+func pbkdf2SHA256(password, salt []byte, iterations, keyLen int) []byte {
+	prf := hmac.New(sha256.New, password)
+	size := prf.Size()
+	blocks := (keyLen + size - 1) / size
+	key := make([]byte, 0, blocks*size)
+	u := make([]byte, size)
+	for block := 1; block <= blocks; block++ {
+		prf.Reset()
+		prf.Write(salt)
+		prf.Write(binary.BigEndian.AppendUint32(nil, uint32(block)))
+		u = prf.Sum(u[:0])
+		t := append([]byte(nil), u...)
+		for i := 1; i < iterations; i++ {
+			prf.Reset()
+			prf.Write(u)
+			u = prf.Sum(u[:0])
+			for j := range t {
+				t[j] ^= u[j]
+			}
+		}
+		key = append(key, t...)
+	}
+	return key[:keyLen]
+}
@@ -0,0 +1,2 @@
+// Package journal queues writes made offline and replays them later.
+package journal
@@ -0,0 +1,192 @@
+package journal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+// Queued operations.
+const (
+	OpTaskAdd       = "task.add"
+	OpTaskUpdate    = "task.update"
+	OpTaskClose     = "task.close"
+	OpTaskReopen    = "task.reopen"
+	OpTaskDelete    = "task.delete"
+	OpCommentAdd    = "comment.add"
+	OpCommentUpdate = "comment.update"
+	OpCommentDelete = "comment.delete"
+)
+
+// TempIDPrefix marks IDs generated locally for objects not yet created.
+const TempIDPrefix = "tmp-"
+
+const (
+	dirPerm  = 0o700
+	filePerm = 0o600
+)
+
+// Entry is one queued write.
+type Entry struct {
+	Seq int    `json:"seq"`
+	Op  string `json:"op"`
+	// ID is the target object, or the temp ID assigned by a create.
+	ID       string         `json:"id,omitempty"`
+	Body     map[string]any `json:"body,omitempty"`
+	QueuedAt time.Time      `json:"queued_at"`
+	// RequestID is sent with every replay of the entry, so a write that
+	// reached the API before an interrupted push is not applied twice.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// Creates reports whether the entry creates a new object.
+func (e Entry) Creates() bool {
+	return e.Op == OpTaskAdd || e.Op == OpCommentAdd
+}
+
+// Journal is an append-only JSONL file of queued writes for one account.
+type Journal struct {
+	Path string
+}
+
+// Open returns the journal for an account key under dir.
+func Open(dir, account string) *Journal {
+	return &Journal{Path: filepath.Join(dir, account+".jsonl")}
+}
+
+// NewTempID returns a local ID for an object created offline.
+func NewTempID() string {
+	return TempIDPrefix + todi.NewTempID()
+}
+
+// IsTempID reports whether id was generated by NewTempID.
+func IsTempID(id string) bool {
+	return strings.HasPrefix(id, TempIDPrefix)
+}
+
+// Append queues a write and returns the stored entry.
+func (j *Journal) Append(op, id string, body map[string]any) (Entry, error) {
+	entries, err := j.Entries()
+	if err != nil {
+		return Entry{}, err
+	}
+	entry := Entry{Seq: 1, Op: op, ID: id, Body: body, QueuedAt: time.Now().UTC(), RequestID: todi.NewRequestID()}
+	if len(entries) > 0 {
+		entry.Seq = entries[len(entries)-1].Seq + 1
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return Entry{}, err
+	}
+	if err := os.MkdirAll(filepath.Dir(j.Path), dirPerm); err != nil {
+		return Entry{}, fmt.Errorf("mkdir journal dir: %w", err)
+	}
+	f, err := os.OpenFile(j.Path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePerm)
+	if err != nil {
+		return Entry{}, fmt.Errorf("open journal: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return Entry{}, fmt.Errorf("write journal: %w", err)
+	}
+	if err := f.Sync(); err != nil {
+		_ = f.Close()
+		return Entry{}, fmt.Errorf("sync journal: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return Entry{}, fmt.Errorf("close journal: %w", err)
+	}
+	return entry, nil
+}
+
+// Entries returns the queued writes in order.
+func (j *Journal) Entries() ([]Entry, error) {
+	data, err := os.ReadFile(j.Path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	var entries []Entry
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		text := bytes.TrimSpace(scanner.Bytes())
+		if len(text) == 0 {
+			continue
+		}
+		var entry Entry
+		if err := json.Unmarshal(text, &entry); err != nil {
+			return nil, fmt.Errorf("parse journal line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read journal: %w", err)
+	}
+	return entries, nil
+}
+
+// Replace rewrites the journal with entries, removing it when empty.
+func (j *Journal) Replace(entries []Entry) error {
+	if len(entries) == 0 {
+		if err := os.Remove(j.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("remove journal: %w", err)
+		}
+		return nil
+	}
+	var buf bytes.Buffer
+	for _, entry := range entries {
+		line, err := json.Marshal(entry)
+		if err != nil {
+			return err
+		}
+		buf.Write(line)
+		buf.WriteByte('\n')
+	}
+	if err := os.MkdirAll(filepath.Dir(j.Path), dirPerm); err != nil {
+		return fmt.Errorf("mkdir journal dir: %w", err)
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(j.Path), ".tmp-*")
+	if err != nil {
+		return fmt.Errorf("write journal: %w", err)
+	}
+	name := tmp.Name()
+	if _, err := tmp.Write(buf.Bytes()); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return fmt.Errorf("write journal: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(name)
+		return fmt.Errorf("sync journal: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(name)
+		return fmt.Errorf("write journal: %w", err)
+	}
+	if err := os.Chmod(name, filePerm); err != nil {
+		_ = os.Remove(name)
+		return fmt.Errorf("write journal: %w", err)
+	}
+	if err := os.Rename(name, j.Path); err != nil {
+		_ = os.Remove(name)
+		return fmt.Errorf("write journal: %w", err)
+	}
+	return nil
+}
+
+// Clear drops every queued write.
+func (j *Journal) Clear() error {
+	return j.Replace(nil)
+}
@@ -0,0 +1,220 @@
+package journal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/mattjefferson/todi/internal/todi"
+)
+
+var errUnknownOp = errors.New("unknown journal op")
+
+// Replay outcomes for a single entry.
+const (
+	StatusApplied  = "applied"
+	StatusConflict = "conflict"
+	StatusRejected = "rejected"
+	StatusPending  = "pending"
+)
+
+// Result is the replay outcome for one entry.
+type Result struct {
+	Entry  Entry  `json:"entry"`
+	Status string `json:"status"`
+	// ID is the real ID assigned to an object created by the entry.
+	ID    string `json:"id,omitempty"`
+	Error string `json:"error,omitempty"`
+}
+
+// Report summarizes a push.
+type Report struct {
+	Results       []Result          `json:"results"`
+	TempIDMapping map[string]string `json:"temp_id_mapping"`
+	Remaining     int               `json:"remaining"`
+}
+
+// Count returns the number of results with status.
+func (r Report) Count(status string) int {
+	count := 0
+	for _, result := range r.Results {
+		if result.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Push replays queued writes in order against the API.
+//
+// Temp IDs are rewritten to real IDs as creates succeed. Entries that hit a
+// missing object are dropped as conflicts and entries the API rejects as
+// invalid are dropped as rejected; both are reported. Any other failure stops
+// the replay, leaving that entry and everything after it in the journal, and
+// is returned with the partial report. The journal is rewritten after every
+// entry so an interrupted push never replays an applied write, and each
+// entry keeps one request ID across replays so the API can deduplicate a
+// write that landed before the journal was rewritten.
+func Push(ctx context.Context, client *todi.Client, j *Journal) (Report, error) {
+	report := Report{Results: []Result{}, TempIDMapping: map[string]string{}}
+	entries, err := j.Entries()
+	if err != nil {
+		return report, err
+	}
+	if err := assignRequestIDs(j, entries); err != nil {
+		return report, err
+	}
+	lost := map[string]bool{}
+	for i := range entries {
+		entry := remapEntry(entries[i], report.TempIDMapping)
+		result := Result{Entry: entry}
+		if ref := lostReference(entry, lost); ref != "" {
+			result.Status = StatusConflict
+			result.Error = fmt.Sprintf("depends on %s, which was not created", ref)
+		} else {
+			id, err := apply(ctx, client, entry)
+			switch {
+			case err == nil:
+				result.Status = StatusApplied
+				if entry.Creates() {
+					result.ID = id
+					report.TempIDMapping[entry.ID] = id
+				}
+			case errors.Is(err, todi.ErrNotFound):
+				result.Status = StatusConflict
+				result.Error = err.Error()
+			case rejected(err):
+				result.Status = StatusRejected
+				result.Error = err.Error()
+			default:
+				remaining := remapEntries(entries[i:], report.TempIDMapping)
+				report.Remaining = len(remaining)
+				for _, pending := range remaining {
+					report.Results = append(report.Results, Result{Entry: pending, Status: StatusPending})
+				}
+				report.Results[len(report.Results)-report.Remaining].Error = err.Error()
+				if replaceErr := j.Replace(remaining); replaceErr != nil {
+					return report, replaceErr
+				}
+				return report, err
+			}
+			if result.Status != StatusApplied && entry.Creates() {
+				lost[entry.ID] = true
+			}
+		}
+		report.Results = append(report.Results, result)
+		if err := j.Replace(remapEntries(entries[i+1:], report.TempIDMapping)); err != nil {
+			return report, err
+		}
+	}
+	return report, nil
+}
+
+// assignRequestIDs gives entries queued without a request ID one and saves
+// it before anything is sent.
+func assignRequestIDs(j *Journal, entries []Entry) error {
+	missing := false
+	for i := range entries {
+		if entries[i].RequestID == "" {
+			entries[i].RequestID = todi.NewRequestID()
+			missing = true
+		}
+	}
+	if !missing {
+		return nil
+	}
+	return j.Replace(entries)
+}
+
+func apply(ctx context.Context, client *todi.Client, entry Entry) (string, error) {
+	ctx = todi.WithRequestID(ctx, entry.RequestID)
+	var err error
+	switch entry.Op {
+	case OpTaskAdd:
+		var task todi.Task
+		task, _, err = client.CreateTask(ctx, entry.Body)
+		return task.ID, err
+	case OpTaskUpdate:
+		_, _, err = client.UpdateTask(ctx, entry.ID, entry.Body)
+	case OpTaskClose:
+		_, err = client.CloseTask(ctx, entry.ID)
+	case OpTaskReopen:
+		_, err = client.ReopenTask(ctx, entry.ID)
+	case OpTaskDelete:
+		_, err = client.DeleteTask(ctx, entry.ID)
+	case OpCommentAdd:
+		var comment todi.Comment
+		comment, _, err = client.CreateComment(ctx, entry.Body)
+		return comment.ID, err
+	case OpCommentUpdate:
+		_, _, err = client.UpdateComment(ctx, entry.ID, entry.Body)
+	case OpCommentDelete:
+		_, err = client.DeleteComment(ctx, entry.ID)
+	default:
+		err = fmt.Errorf("%w: %s", errUnknownOp, entry.Op)
+	}
+	return "", err
+}
+
+// rejected reports failures that will not succeed on retry.
+func rejected(err error) bool {
+	if errors.Is(err, errUnknownOp) {
+		return true
+	}
+	var apiErr *todi.APIError
+	if !errors.As(err, &apiErr) {
+		return false
+	}
+	switch apiErr.StatusCode {
+	case http.StatusBadRequest, http.StatusUnprocessableEntity, http.StatusGone:
+		return true
+	default:
+		return false
+	}
+}
+
+// lostReference returns a temp ID the entry needs whose create did not apply.
+func lostReference(entry Entry, lost map[string]bool) string {
+	if !entry.Creates() && lost[entry.ID] {
+		return entry.ID
+	}
+	for _, value := range entry.Body {
+		if id, ok := value.(string); ok && lost[id] {
+			return id
+		}
+	}
+	return ""
+}
+
+func remapEntries(entries []Entry, mapping map[string]string) []Entry {
+	remapped := make([]Entry, 0, len(entries))
+	for _, entry := range entries {
+		remapped = append(remapped, remapEntry(entry, mapping))
+	}
+	return remapped
+}
+
+// remapEntry rewrites temp IDs that have since been created to real IDs.
+func remapEntry(entry Entry, mapping map[string]string) Entry {
+	if len(mapping) == 0 {
+		return entry
+	}
+	if realID, ok := mapping[entry.ID]; ok && !entry.Creates() {
+		entry.ID = realID
+	}
+	if len(entry.Body) == 0 {
+		return entry
+	}
+	body := make(map[string]any, len(entry.Body))
+	for key, value := range entry.Body {
+		if id, ok := value.(string); ok {
+			if realID, ok := mapping[id]; ok {
+				value = realID
+			}
+		}
+		body[key] = value
+	}
+	entry.Body = body
+	return entry
+}
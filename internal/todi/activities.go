@@ -2,7 +2,6 @@ package todi
 
 import (
 	"context"
-	"strconv"
 )
 
 // ListActivities fetches a page of activity log entries.
@@ -16,25 +15,13 @@ func (c *Client) ListActivities(ctx context.Context, params map[string]string) (
 
 // ListActivitiesAll fetches all activity log entries across pages.
 func (c *Client) ListActivitiesAll(ctx context.Context, params map[string]string) ([]Activity, error) {
-	if params == nil {
-		params = map[string]string{}
-	}
-	params["limit"] = strconv.Itoa(100)
 	var all []Activity
-	cursor := ""
-	for {
-		if cursor != "" {
-			params["cursor"] = cursor
-		}
-		page, next, err := c.ListActivities(ctx, params)
-		if err != nil {
-			return nil, err
-		}
+	err := eachPage(ctx, params, 100, c.ListActivities, func(page []Activity) error {
 		all = append(all, page...)
-		if next == "" {
-			break
-		}
-		cursor = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return all, nil
 }
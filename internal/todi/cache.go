@@ -0,0 +1,61 @@
+package todi
+
+import (
+	"net/url"
+	"os"
+)
+
+// Cache stores decoded collection listings between runs.
+//
+// Implementations are best-effort: Get reports a miss on any failure and
+// Put and Invalidate swallow errors, so a broken cache never fails a request.
+type Cache interface {
+	Get(key string, out any) bool
+	Put(key string, value any)
+	Invalidate()
+}
+
+// cacheKey builds a stable key for a listing from its filter params.
+func cacheKey(resource string, params map[string]string) string {
+	q := url.Values{}
+	for key, value := range params {
+		if key == "limit" || key == "cursor" || value == "" {
+			continue
+		}
+		q.Set(key, value)
+	}
+	if len(q) == 0 {
+		return resource
+	}
+	return resource + "?" + q.Encode()
+}
+
+func (c *Client) cacheGet(key string, out any) bool {
+	if c.Cache == nil {
+		return false
+	}
+	if !c.Cache.Get(key, out) {
+		return false
+	}
+	if c.Verbose {
+		writef(os.Stderr, "cache hit %s\n", key)
+	}
+	return true
+}
+
+func (c *Client) cachePut(key string, value any) {
+	if c.Cache == nil {
+		return
+	}
+	c.Cache.Put(key, value)
+}
+
+// invalidate drops cached listings after a write and marks the sync state
+// for a refresh.
+func (c *Client) invalidate() {
+	c.staleSync()
+	if c.Cache == nil {
+		return
+	}
+	c.Cache.Invalidate()
+}
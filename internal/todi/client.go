@@ -3,13 +3,16 @@ package todi
 import (
 	"bytes"
 	"context"
+	"crypto/rand"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
@@ -19,6 +22,21 @@ type Client struct {
 	Token   string
 	HTTP    *http.Client
 	Verbose bool
+	Retry   RetryPolicy
+	Cache   Cache
+	// Offline fails every request with ErrOffline; cached reads still work.
+	Offline bool
+	// Match holds the match modes of name lookups per resource; see
+	// ParseMatchModes. Resources without modes use DefaultMatchModes.
+	Match map[string][]MatchMode
+	// Choose, if set, settles ambiguous name lookups.
+	Choose Chooser
+	// Limiter, if set, paces every request attempt, retries included.
+	Limiter *RateLimiter
+
+	syncMu      sync.Mutex
+	syncState   *SyncState
+	syncCurrent bool
 }
 
 // NewClient creates a Todoist API client.
@@ -30,6 +48,7 @@ func NewClient(baseURL, token string, verbose bool) *Client {
 			Timeout: 30 * time.Second,
 		},
 		Verbose: verbose,
+		Retry:   DefaultRetryPolicy(),
 	}
 }
 
@@ -38,6 +57,37 @@ type listResponse[T any] struct {
 	NextCursor string `json:"next_cursor"`
 }
 
+// EachPage walks a paged listing with the largest page size, calling fn
+// with each page as it arrives.
+func EachPage[T any](ctx context.Context, params map[string]string, list func(context.Context, map[string]string) ([]T, string, error), fn func([]T) error) error {
+	return eachPage(ctx, params, 200, list, fn)
+}
+
+// eachPage is EachPage for listings with a smaller maximum page size.
+func eachPage[T any](ctx context.Context, params map[string]string, limit int, list func(context.Context, map[string]string) ([]T, string, error), fn func([]T) error) error {
+	if params == nil {
+		params = map[string]string{}
+	}
+	params["limit"] = strconv.Itoa(limit)
+	cursor := ""
+	for {
+		if cursor != "" {
+			params["cursor"] = cursor
+		}
+		page, next, err := list(ctx, params)
+		if err != nil {
+			return err
+		}
+		if err := fn(page); err != nil {
+			return err
+		}
+		if next == "" {
+			return nil
+		}
+		cursor = next
+	}
+}
+
 func (c *Client) get(ctx context.Context, path string, params map[string]string, out any) error {
 	fullURL, err := c.url(path, params)
 	if err != nil {
@@ -71,9 +121,33 @@ func (c *Client) post(ctx context.Context, path string, body map[string]any, out
 	if body != nil {
 		req.Header.Set("Content-Type", "application/json")
 	}
+	req.Header.Set("X-Request-Id", requestID(ctx))
+	defer c.invalidate()
 	return c.do(req, out)
 }
 
+func (c *Client) postForm(ctx context.Context, path string, form url.Values, out any) ([]byte, error) {
+	req, err := c.formRequest(ctx, path, form)
+	if err != nil {
+		return nil, err
+	}
+	return c.do(req, out)
+}
+
+func (c *Client) formRequest(ctx context.Context, path string, form url.Values) (*http.Request, error) {
+	fullURL, err := c.url(path, nil)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, fullURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("X-Request-Id", requestID(ctx))
+	return req, nil
+}
+
 func (c *Client) delete(ctx context.Context, path string) ([]byte, error) {
 	fullURL, err := c.url(path, nil)
 	if err != nil {
@@ -83,6 +157,7 @@ func (c *Client) delete(ctx context.Context, path string) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	defer c.invalidate()
 	return c.do(req, nil)
 }
 
@@ -95,6 +170,7 @@ func (c *Client) deleteWithParams(ctx context.Context, path string, params map[s
 	if err != nil {
 		return nil, err
 	}
+	defer c.invalidate()
 	return c.do(req, nil)
 }
 
@@ -118,13 +194,57 @@ func (c *Client) url(path string, params map[string]string) (string, error) {
 }
 
 func (c *Client) do(req *http.Request, out any) ([]byte, error) {
-	req.Header.Set("Authorization", "Bearer "+c.Token)
-	if c.Verbose {
-		writef(os.Stderr, "%s %s\n", req.Method, req.URL.String())
+	if c.Offline {
+		return nil, fmt.Errorf("%w: %s %s needs the network", ErrOffline, req.Method, req.URL.Path)
+	}
+	if c.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.Token)
 	}
+	replayable := req.Body == nil || req.GetBody != nil
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		if err := c.Limiter.Wait(req.Context()); err != nil {
+			return nil, err
+		}
+		if c.Verbose {
+			writef(os.Stderr, "%s %s\n", req.Method, req.URL.String())
+		}
+		resp, body, err := c.send(req)
+		if replayable && attempt < c.Retry.MaxRetries && shouldRetry(req, resp, err) {
+			wait := c.Retry.wait(attempt+1, resp)
+			if c.Verbose {
+				writef(os.Stderr, "retry %d/%d in %s: %s\n", attempt+1, c.Retry.MaxRetries, wait.Round(time.Millisecond), retryReason(resp, err))
+			}
+			if err := sleep(req.Context(), wait); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode >= 400 {
+			return nil, newAPIError(req, resp, body)
+		}
+		if out != nil && len(body) > 0 {
+			if err := json.Unmarshal(body, out); err != nil {
+				return body, fmt.Errorf("decode response: %w", err)
+			}
+		}
+		return body, nil
+	}
+}
+
+func (c *Client) send(req *http.Request) (*http.Response, []byte, error) {
 	resp, err := c.HTTP.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
 	defer func() {
 		if err := resp.Body.Close(); err != nil {
@@ -133,15 +253,47 @@ func (c *Client) do(req *http.Request, out any) ([]byte, error) {
 	}()
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return resp, nil, err
 	}
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("api error: %s: %s", resp.Status, strings.TrimSpace(string(body)))
+	return resp, body, nil
+}
+
+func retryReason(resp *http.Response, err error) string {
+	if err != nil {
+		return err.Error()
 	}
-	if out != nil && len(body) > 0 {
-		if err := json.Unmarshal(body, out); err != nil {
-			return body, fmt.Errorf("decode response: %w", err)
-		}
+	return resp.Status
+}
+
+type requestIDKey struct{}
+
+// WithRequestID makes POST requests sent with ctx use id as their
+// X-Request-Id instead of a fresh one, so the API can deduplicate a write
+// that is sent again later.
+func WithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// NewRequestID returns a random request ID for WithRequestID.
+func NewRequestID() string {
+	return newRequestID()
+}
+
+// requestID returns the request ID set on ctx, or a fresh one.
+func requestID(ctx context.Context) string {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok && id != "" {
+		return id
+	}
+	return newRequestID()
+}
+
+// newRequestID returns a random UUIDv4 used to make POST retries idempotent.
+func newRequestID() string {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return ""
 	}
-	return body, nil
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
 }
@@ -3,7 +3,6 @@ package todi
 import (
 	"context"
 	"net/url"
-	"strconv"
 )
 
 // ListComments fetches a page of comments.
@@ -17,25 +16,13 @@ func (c *Client) ListComments(ctx context.Context, params map[string]string) ([]
 
 // ListCommentsAll fetches all comments across pages.
 func (c *Client) ListCommentsAll(ctx context.Context, params map[string]string) ([]Comment, error) {
-	if params == nil {
-		params = map[string]string{}
-	}
-	params["limit"] = strconv.Itoa(200)
 	var all []Comment
-	cursor := ""
-	for {
-		if cursor != "" {
-			params["cursor"] = cursor
-		}
-		page, next, err := c.ListComments(ctx, params)
-		if err != nil {
-			return nil, err
-		}
+	err := EachPage(ctx, params, c.ListComments, func(page []Comment) error {
 		all = append(all, page...)
-		if next == "" {
-			break
-		}
-		cursor = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return all, nil
 }
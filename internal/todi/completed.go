@@ -0,0 +1,49 @@
+package todi
+
+import (
+	"context"
+)
+
+// ListCompletedTasks fetches a page of tasks completed within a date range.
+//
+// params must include since and until; the API accepts ranges of up to
+// three months.
+func (c *Client) ListCompletedTasks(ctx context.Context, params map[string]string) ([]Task, string, error) {
+	var resp struct {
+		Items      []Task `json:"items"`
+		NextCursor string `json:"next_cursor"`
+	}
+	if err := c.get(ctx, "/api/v1/tasks/completed/by_completion_date", params, &resp); err != nil {
+		return nil, "", err
+	}
+	return resp.Items, resp.NextCursor, nil
+}
+
+// ListCompletedTasksAll fetches all completed tasks in a range across pages.
+func (c *Client) ListCompletedTasksAll(ctx context.Context, params map[string]string) ([]Task, error) {
+	var all []Task
+	err := EachPage(ctx, params, c.ListCompletedTasks, func(page []Task) error {
+		all = append(all, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return all, nil
+}
+
+// FindCompletedTaskByContent resolves a title against the tasks completed
+// in the range and project of params.
+func (c *Client) FindCompletedTaskByContent(ctx context.Context, title string, params map[string]string) (Task, error) {
+	query := map[string]string{}
+	for _, key := range []string{"since", "until", "project_id"} {
+		if params[key] != "" {
+			query[key] = params[key]
+		}
+	}
+	tasks, err := c.ListCompletedTasksAll(ctx, query)
+	if err != nil {
+		return Task{}, err
+	}
+	return findByName(ctx, c, "task", "title", title, tasks, taskNamed)
+}
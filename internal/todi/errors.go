@@ -0,0 +1,100 @@
+package todi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Lookup failure causes, matched with errors.Is.
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrAmbiguous = errors.New("not unique")
+)
+
+// ErrOffline is returned for requests made while the client is offline.
+var ErrOffline = errors.New("offline")
+
+// APIError describes a non-2xx response from the Todoist API.
+type APIError struct {
+	StatusCode int
+	Status     string
+	ErrorCode  int
+	ErrorTag   string
+	Message    string
+	RequestID  string
+	Retryable  bool
+	Body       string
+}
+
+func (e *APIError) Error() string {
+	detail := e.Message
+	if detail == "" {
+		detail = e.Body
+	}
+	return fmt.Sprintf("api error: %s: %s", e.Status, detail)
+}
+
+// Is reports 404 responses as ErrNotFound.
+func (e *APIError) Is(target error) bool {
+	return target == ErrNotFound && e.StatusCode == http.StatusNotFound
+}
+
+func newAPIError(req *http.Request, resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{
+		StatusCode: resp.StatusCode,
+		Status:     resp.Status,
+		RequestID:  firstHeader(resp.Header, req.Header, "X-Request-Id"),
+		Retryable:  retryableStatus(resp.StatusCode),
+		Body:       strings.TrimSpace(string(body)),
+	}
+	var payload struct {
+		Error     string `json:"error"`
+		ErrorCode int    `json:"error_code"`
+		ErrorTag  string `json:"error_tag"`
+	}
+	if err := json.Unmarshal(body, &payload); err == nil {
+		apiErr.Message = payload.Error
+		apiErr.ErrorCode = payload.ErrorCode
+		apiErr.ErrorTag = payload.ErrorTag
+	}
+	return apiErr
+}
+
+func firstHeader(primary, fallback http.Header, key string) string {
+	if value := primary.Get(key); value != "" {
+		return value
+	}
+	return fallback.Get(key)
+}
+
+// LookupError reports a name lookup that matched zero or several resources.
+type LookupError struct {
+	Resource string
+	Field    string
+	Query    string
+	Err      error
+	// Candidates lists the matches of an ambiguous lookup.
+	Candidates []Candidate
+}
+
+func (e *LookupError) Error() string {
+	if errors.Is(e.Err, ErrAmbiguous) {
+		msg := fmt.Sprintf("%s %s not unique: %s", e.Resource, e.Field, e.Query)
+		for _, candidate := range e.Candidates {
+			msg += "\n  " + candidate.String()
+		}
+		return msg
+	}
+	return fmt.Sprintf("%s not found: %s", e.Resource, e.Query)
+}
+
+func (e *LookupError) Unwrap() error {
+	return e.Err
+}
+
+func notFound(resource, field, query string) error {
+	return &LookupError{Resource: resource, Field: field, Query: query, Err: ErrNotFound}
+}
@@ -0,0 +1,68 @@
+package todi
+
+import (
+	"context"
+	"sort"
+)
+
+// ListFilters returns every saved filter, ordered as in the apps, from the
+// client's sync state, fetching only what changed since the last sync.
+func (c *Client) ListFilters(ctx context.Context) ([]Filter, error) {
+	state, err := c.synced(ctx)
+	if err != nil {
+		return nil, err
+	}
+	filters := append([]Filter{}, state.Filters...)
+	sort.SliceStable(filters, func(i, j int) bool { return filters[i].ItemOrder < filters[j].ItemOrder })
+	return filters, nil
+}
+
+// GetFilter returns the filter with the given ID.
+func (c *Client) GetFilter(ctx context.Context, id string) (Filter, error) {
+	filters, err := c.ListFilters(ctx)
+	if err != nil {
+		return Filter{}, err
+	}
+	for _, filter := range filters {
+		if filter.ID == id {
+			return filter, nil
+		}
+	}
+	return Filter{}, notFound("filter", "id", id)
+}
+
+// FindFilterByName returns the filter matching name under the client's match
+// modes for filters.
+func (c *Client) FindFilterByName(ctx context.Context, name string) (Filter, error) {
+	filters, err := c.ListFilters(ctx)
+	if err != nil {
+		return Filter{}, err
+	}
+	return findByName(ctx, c, "filter", "name", name, filters, func(item Filter) named {
+		return named{ID: item.ID, Name: item.Name}
+	})
+}
+
+// AddFilter creates a filter and returns its ID. args follow the Sync API
+// filter_add command and must include name and query.
+func (c *Client) AddFilter(ctx context.Context, args map[string]any) (string, error) {
+	cmd := NewCommand("filter_add", args)
+	cmd.TempID = NewTempID()
+	return c.executeSynced(ctx, cmd)
+}
+
+// UpdateFilter changes an existing filter.
+func (c *Client) UpdateFilter(ctx context.Context, id string, args map[string]any) error {
+	update := map[string]any{"id": id}
+	for key, value := range args {
+		update[key] = value
+	}
+	_, err := c.executeSynced(ctx, NewCommand("filter_update", update))
+	return err
+}
+
+// DeleteFilter deletes a filter by ID.
+func (c *Client) DeleteFilter(ctx context.Context, id string) error {
+	_, err := c.executeSynced(ctx, NewCommand("filter_delete", map[string]any{"id": id}))
+	return err
+}
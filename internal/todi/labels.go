@@ -2,9 +2,8 @@ package todi
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/url"
-	"strconv"
 )
 
 // ListLabels fetches a page of labels.
@@ -18,26 +17,19 @@ func (c *Client) ListLabels(ctx context.Context, params map[string]string) ([]La
 
 // ListLabelsAll fetches all labels across pages.
 func (c *Client) ListLabelsAll(ctx context.Context, params map[string]string) ([]Label, error) {
-	if params == nil {
-		params = map[string]string{}
-	}
-	params["limit"] = strconv.Itoa(200)
 	var all []Label
-	cursor := ""
-	for {
-		if cursor != "" {
-			params["cursor"] = cursor
-		}
-		page, next, err := c.ListLabels(ctx, params)
-		if err != nil {
-			return nil, err
-		}
+	key := cacheKey("labels", params)
+	if c.cacheGet(key, &all) {
+		return all, nil
+	}
+	err := EachPage(ctx, params, c.ListLabels, func(page []Label) error {
 		all = append(all, page...)
-		if next == "" {
-			break
-		}
-		cursor = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	c.cachePut(key, all)
 	return all, nil
 }
 
@@ -69,25 +61,29 @@ func (c *Client) DeleteLabel(ctx context.Context, id string) ([]byte, error) {
 	return c.delete(ctx, "/api/v1/labels/"+url.PathEscape(id))
 }
 
-// FindLabelByName returns the label for a unique name match.
+// FindLabelByName returns the label matching name under the client's match
+// modes for labels.
 func (c *Client) FindLabelByName(ctx context.Context, name string) (Label, error) {
-	labels, err := c.ListLabelsAll(ctx, nil)
+	labels, err := c.lookupLabels(ctx)
 	if err != nil {
 		return Label{}, err
 	}
-	matches := make([]Label, 0, 2)
-	for _, label := range labels {
-		if label.Name == name {
-			matches = append(matches, label)
-		}
-	}
-	if len(matches) == 0 {
-		return Label{}, fmt.Errorf("label not found: %s", name)
+	return findByName(ctx, c, "label", "name", name, labels, func(item Label) named {
+		return named{ID: item.ID, Name: item.Name}
+	})
+}
+
+// lookupLabels returns the labels that name lookups search: those of the
+// sync state, or the cached label listing when offline without one.
+func (c *Client) lookupLabels(ctx context.Context) ([]Label, error) {
+	state, err := c.synced(ctx)
+	if errors.Is(err, ErrOffline) {
+		return c.ListLabelsAll(ctx, nil)
 	}
-	if len(matches) > 1 {
-		return Label{}, fmt.Errorf("label name not unique: %s", name)
+	if err != nil {
+		return nil, err
 	}
-	return matches[0], nil
+	return state.Labels, nil
 }
 
 // FindLabelIDByName returns the label ID for a unique name match.
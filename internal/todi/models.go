@@ -1,35 +1,138 @@
 package todi
 
+import "time"
+
 // Task represents a Todoist task.
 type Task struct {
-	ID          string   `json:"id"`
-	Content     string   `json:"content"`
-	Description string   `json:"description,omitempty"`
-	ProjectID   string   `json:"project_id"`
-	Labels      []string `json:"labels"`
-	Priority    int      `json:"priority"`
-	Due         *Due     `json:"due"`
+	ID           string    `json:"id"`
+	UserID       string    `json:"user_id,omitempty"`
+	Content      string    `json:"content"`
+	Description  string    `json:"description,omitempty"`
+	ProjectID    string    `json:"project_id"`
+	SectionID    string    `json:"section_id,omitempty"`
+	ParentID     string    `json:"parent_id,omitempty"`
+	ChildOrder   int       `json:"child_order"`
+	DayOrder     int       `json:"day_order,omitempty"`
+	Labels       []string  `json:"labels"`
+	Priority     int       `json:"priority"`
+	Due          *Due      `json:"due"`
+	Deadline     *Deadline `json:"deadline,omitempty"`
+	Duration     *Duration `json:"duration,omitempty"`
+	CreatorID    string    `json:"added_by_uid,omitempty"`
+	AssigneeID   string    `json:"responsible_uid,omitempty"`
+	AssignerID   string    `json:"assigned_by_uid,omitempty"`
+	AddedAt      string    `json:"added_at,omitempty"`
+	UpdatedAt    string    `json:"updated_at,omitempty"`
+	CompletedAt  string    `json:"completed_at,omitempty"`
+	CommentCount int       `json:"note_count"`
+	IsCompleted  bool      `json:"checked"`
+	IsCollapsed  bool      `json:"is_collapsed,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	IsDeleted    bool      `json:"is_deleted,omitempty"`
+}
+
+// IsRecurring reports whether the task repeats.
+func (t Task) IsRecurring() bool {
+	return t.Due != nil && t.Due.IsRecurring
+}
+
+// PriorityLabel returns the priority as shown in the Todoist apps, where
+// API priority 4 is p1 (urgent) and 1 is p4 (normal).
+func (t Task) PriorityLabel() string {
+	if t.Priority < 1 || t.Priority > 4 {
+		return ""
+	}
+	return "p" + string(rune('0'+5-t.Priority))
+}
+
+// WebURL returns the task link, falling back to the web app URL for its ID.
+func (t Task) WebURL() string {
+	if t.URL != "" || t.ID == "" {
+		return t.URL
+	}
+	return "https://app.todoist.com/app/task/" + t.ID
 }
 
 // Due represents a task due date or datetime.
 type Due struct {
-	Date     string `json:"date"`
-	Datetime string `json:"datetime"`
-	String   string `json:"string"`
-	Timezone string `json:"timezone"`
+	Date        string `json:"date"`
+	Datetime    string `json:"datetime"`
+	String      string `json:"string"`
+	Timezone    string `json:"timezone"`
+	Lang        string `json:"lang,omitempty"`
+	IsRecurring bool   `json:"is_recurring"`
+}
+
+// Deadline represents a task deadline.
+type Deadline struct {
+	Date string `json:"date"`
+	Lang string `json:"lang,omitempty"`
+}
+
+// Duration represents the time a task is expected to take.
+type Duration struct {
+	Amount int    `json:"amount"`
+	Unit   string `json:"unit"`
 }
 
 // Project represents a Todoist project.
 type Project struct {
-	ID   string `json:"id"`
-	Name string `json:"name"`
+	ID             string `json:"id"`
+	Name           string `json:"name"`
+	Description    string `json:"description,omitempty"`
+	ParentID       string `json:"parent_id,omitempty"`
+	ChildOrder     int    `json:"child_order"`
+	Color          string `json:"color,omitempty"`
+	ViewStyle      string `json:"view_style,omitempty"`
+	CreatorID      string `json:"creator_uid,omitempty"`
+	CreatedAt      string `json:"created_at,omitempty"`
+	UpdatedAt      string `json:"updated_at,omitempty"`
+	IsFavorite     bool   `json:"is_favorite"`
+	IsShared       bool   `json:"is_shared"`
+	IsArchived     bool   `json:"is_archived"`
+	IsCollapsed    bool   `json:"is_collapsed,omitempty"`
+	IsInbox        bool   `json:"inbox_project,omitempty"`
+	CanAssignTasks bool   `json:"can_assign_tasks,omitempty"`
+	Role           string `json:"role,omitempty"`
+	IsDeleted      bool   `json:"is_deleted,omitempty"`
 }
 
 // User represents the currently authenticated Todoist user.
 type User struct {
-	ID       string `json:"id"`
-	Email    string `json:"email"`
-	FullName string `json:"full_name"`
+	ID       string  `json:"id"`
+	Email    string  `json:"email"`
+	FullName string  `json:"full_name"`
+	TZInfo   *TZInfo `json:"tz_info,omitempty"`
+}
+
+// TZInfo is the timezone from the user's settings.
+type TZInfo struct {
+	Timezone  string `json:"timezone"`
+	GMTString string `json:"gmt_string,omitempty"`
+	Hours     int    `json:"hours"`
+	Minutes   int    `json:"minutes"`
+}
+
+// Location returns the user's timezone. It falls back to the fixed GMT
+// offset when the zone name is unknown, and to the local zone when the
+// user has no timezone set.
+func (u User) Location() *time.Location {
+	if u.TZInfo == nil {
+		return time.Local
+	}
+	if u.TZInfo.Timezone != "" {
+		if loc, err := time.LoadLocation(u.TZInfo.Timezone); err == nil {
+			return loc
+		}
+	}
+	if u.TZInfo.Hours == 0 && u.TZInfo.Minutes == 0 && u.TZInfo.GMTString == "" {
+		return time.Local
+	}
+	offset := u.TZInfo.Hours*3600 + u.TZInfo.Minutes*60
+	if u.TZInfo.Hours < 0 {
+		offset = u.TZInfo.Hours*3600 - u.TZInfo.Minutes*60
+	}
+	return time.FixedZone(u.TZInfo.GMTString, offset)
 }
 
 // Section represents a Todoist section.
@@ -54,6 +157,34 @@ type Label struct {
 	Color      string `json:"color,omitempty"`
 	Order      int    `json:"order,omitempty"`
 	IsFavorite bool   `json:"is_favorite,omitempty"`
+	IsDeleted  bool   `json:"is_deleted,omitempty"`
+}
+
+// Reminder represents a Todoist task reminder.
+type Reminder struct {
+	ID           string `json:"id"`
+	NotifyUID    string `json:"notify_uid,omitempty"`
+	ItemID       string `json:"item_id"`
+	Type         string `json:"type"`
+	Due          *Due   `json:"due,omitempty"`
+	MinuteOffset *int   `json:"minute_offset,omitempty"`
+	Name         string `json:"name,omitempty"`
+	LocLat       string `json:"loc_lat,omitempty"`
+	LocLong      string `json:"loc_long,omitempty"`
+	LocTrigger   string `json:"loc_trigger,omitempty"`
+	Radius       int    `json:"radius,omitempty"`
+	IsDeleted    bool   `json:"is_deleted,omitempty"`
+}
+
+// Filter represents a saved Todoist filter.
+type Filter struct {
+	ID         string `json:"id"`
+	Name       string `json:"name"`
+	Query      string `json:"query"`
+	Color      string `json:"color,omitempty"`
+	ItemOrder  int    `json:"item_order"`
+	IsFavorite bool   `json:"is_favorite"`
+	IsDeleted  bool   `json:"is_deleted,omitempty"`
 }
 
 // Upload represents a Todoist upload response.
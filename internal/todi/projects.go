@@ -2,7 +2,7 @@ package todi
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/url"
 )
 
@@ -17,23 +17,19 @@ func (c *Client) ListProjects(ctx context.Context, params map[string]string) ([]
 
 // ListProjectsAll fetches all projects across pages.
 func (c *Client) ListProjectsAll(ctx context.Context) ([]Project, error) {
-	params := map[string]string{"limit": "200"}
 	var all []Project
-	cursor := ""
-	for {
-		if cursor != "" {
-			params["cursor"] = cursor
-		}
-		page, next, err := c.ListProjects(ctx, params)
-		if err != nil {
-			return nil, err
-		}
+	key := cacheKey("projects", nil)
+	if c.cacheGet(key, &all) {
+		return all, nil
+	}
+	err := EachPage(ctx, nil, c.ListProjects, func(page []Project) error {
 		all = append(all, page...)
-		if next == "" {
-			break
-		}
-		cursor = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	c.cachePut(key, all)
 	return all, nil
 }
 
@@ -75,25 +71,35 @@ func (c *Client) UnarchiveProject(ctx context.Context, id string) ([]byte, error
 	return c.post(ctx, "/api/v1/projects/"+url.PathEscape(id)+"/unarchive", nil, nil)
 }
 
-// FindProjectByName returns the project for a unique name match.
+// FindProjectByName returns the project matching name under the client's match
+// modes for projects.
 func (c *Client) FindProjectByName(ctx context.Context, name string) (Project, error) {
-	projects, err := c.ListProjectsAll(ctx)
+	projects, err := c.lookupProjects(ctx)
 	if err != nil {
 		return Project{}, err
 	}
-	matches := make([]Project, 0, 2)
-	for _, project := range projects {
-		if project.Name == name {
-			matches = append(matches, project)
-		}
+	return findByName(ctx, c, "project", "name", name, projects, func(item Project) named {
+		return named{ID: item.ID, Name: item.Name, ProjectID: item.ParentID}
+	})
+}
+
+// lookupProjects returns the active projects that name lookups search: those
+// of the sync state, or the cached project listing when offline without one.
+func (c *Client) lookupProjects(ctx context.Context) ([]Project, error) {
+	state, err := c.synced(ctx)
+	if errors.Is(err, ErrOffline) {
+		return c.ListProjectsAll(ctx)
 	}
-	if len(matches) == 0 {
-		return Project{}, fmt.Errorf("project not found: %s", name)
+	if err != nil {
+		return nil, err
 	}
-	if len(matches) > 1 {
-		return Project{}, fmt.Errorf("project name not unique: %s", name)
+	projects := make([]Project, 0, len(state.Projects))
+	for _, project := range state.Projects {
+		if !project.IsArchived {
+			projects = append(projects, project)
+		}
 	}
-	return matches[0], nil
+	return projects, nil
 }
 
 // FindProjectIDByName returns the project ID for a unique project name.
@@ -0,0 +1,73 @@
+package todi
+
+import "context"
+
+// Reminder types.
+const (
+	ReminderRelative = "relative"
+	ReminderAbsolute = "absolute"
+	ReminderLocation = "location"
+)
+
+// ListReminders returns every reminder for the account from the client's
+// sync state, fetching only what changed since the last sync.
+func (c *Client) ListReminders(ctx context.Context) ([]Reminder, error) {
+	state, err := c.synced(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return append([]Reminder{}, state.Reminders...), nil
+}
+
+// ListTaskReminders fetches the reminders for one task.
+func (c *Client) ListTaskReminders(ctx context.Context, taskID string) ([]Reminder, error) {
+	reminders, err := c.ListReminders(ctx)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]Reminder, 0, len(reminders))
+	for _, reminder := range reminders {
+		if reminder.ItemID == taskID {
+			matches = append(matches, reminder)
+		}
+	}
+	return matches, nil
+}
+
+// FindReminder returns the reminder with the given ID.
+func (c *Client) FindReminder(ctx context.Context, id string) (Reminder, error) {
+	reminders, err := c.ListReminders(ctx)
+	if err != nil {
+		return Reminder{}, err
+	}
+	for _, reminder := range reminders {
+		if reminder.ID == id {
+			return reminder, nil
+		}
+	}
+	return Reminder{}, notFound("reminder", "id", id)
+}
+
+// AddReminder creates a reminder and returns its ID. args follow the Sync
+// API reminder_add command and must include item_id and type.
+func (c *Client) AddReminder(ctx context.Context, args map[string]any) (string, error) {
+	cmd := NewCommand("reminder_add", args)
+	cmd.TempID = NewTempID()
+	return c.executeSynced(ctx, cmd)
+}
+
+// UpdateReminder changes an existing reminder.
+func (c *Client) UpdateReminder(ctx context.Context, id string, args map[string]any) error {
+	update := map[string]any{"id": id}
+	for key, value := range args {
+		update[key] = value
+	}
+	_, err := c.executeSynced(ctx, NewCommand("reminder_update", update))
+	return err
+}
+
+// DeleteReminder deletes a reminder by ID.
+func (c *Client) DeleteReminder(ctx context.Context, id string) error {
+	_, err := c.executeSynced(ctx, NewCommand("reminder_delete", map[string]any{"id": id}))
+	return err
+}
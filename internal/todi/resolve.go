@@ -0,0 +1,196 @@
+package todi
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// MatchMode is one way of matching a name lookup against resources.
+type MatchMode string
+
+// Match modes, from strictest to loosest. Prefix, substring and fuzzy
+// matches ignore case; fuzzy matches the query's characters in order.
+const (
+	MatchExact     MatchMode = "exact"
+	MatchFold      MatchMode = "case-insensitive"
+	MatchPrefix    MatchMode = "prefix"
+	MatchSubstring MatchMode = "substring"
+	MatchFuzzy     MatchMode = "fuzzy"
+	MatchIDPrefix  MatchMode = "id-prefix"
+)
+
+// MatchResources are the resources whose lookups can be configured.
+var MatchResources = []string{"task", "project", "section", "label", "filter"}
+
+// DefaultMatchModes keeps lookups exact unless configured otherwise.
+var DefaultMatchModes = []MatchMode{MatchExact}
+
+// ParseMatchModes parses a comma-separated list of match modes, tried in
+// order by name lookups.
+func ParseMatchModes(value string) ([]MatchMode, error) {
+	var modes []MatchMode
+	for _, part := range strings.Split(value, ",") {
+		mode := MatchMode(strings.ToLower(strings.TrimSpace(part)))
+		switch mode {
+		case "":
+			continue
+		case MatchExact, MatchFold, MatchPrefix, MatchSubstring, MatchFuzzy, MatchIDPrefix:
+			modes = append(modes, mode)
+		default:
+			return nil, fmt.Errorf("unknown match mode: %s (use exact, case-insensitive, prefix, substring, fuzzy or id-prefix)", part)
+		}
+	}
+	if len(modes) == 0 {
+		return nil, fmt.Errorf("at least one match mode required")
+	}
+	return modes, nil
+}
+
+// Candidate describes one of several resources matching a lookup.
+type Candidate struct {
+	ID      string `json:"id"`
+	Name    string `json:"name"`
+	Project string `json:"project,omitempty"`
+}
+
+// String formats the candidate as "<id>  <name>  (<project>)".
+func (c Candidate) String() string {
+	if c.Project == "" {
+		return c.ID + "  " + c.Name
+	}
+	return fmt.Sprintf("%s  %s  (%s)", c.ID, c.Name, c.Project)
+}
+
+// Chooser picks one of err.Candidates for an ambiguous lookup and returns
+// its index.
+type Chooser func(err *LookupError) (int, error)
+
+// named is the part of a resource that name lookups see.
+type named struct {
+	ID        string
+	Name      string
+	ProjectID string
+}
+
+func (c *Client) matchModes(resource string) []MatchMode {
+	if modes := c.Match[resource]; len(modes) > 0 {
+		return modes
+	}
+	return DefaultMatchModes
+}
+
+// findByName resolves query against items with the client's match modes
+// for resource. Modes are tried in order and the first one with any match
+// decides; several matches go to the client's Chooser, if set, or fail with
+// the candidates listed.
+func findByName[T any](ctx context.Context, c *Client, resource, field, query string, items []T, fields func(T) named) (T, error) {
+	var zero T
+	all := make([]named, len(items))
+	for i, item := range items {
+		all[i] = fields(item)
+	}
+	for _, mode := range c.matchModes(resource) {
+		hits := matchNames(mode, query, all)
+		if len(hits) == 0 {
+			continue
+		}
+		if len(hits) == 1 {
+			return items[hits[0]], nil
+		}
+		lookupErr := &LookupError{Resource: resource, Field: field, Query: query, Err: ErrAmbiguous}
+		projects := c.projectNames(ctx, resource)
+		for _, i := range hits {
+			project := all[i].ProjectID
+			if name, ok := projects[project]; ok {
+				project = name
+			}
+			lookupErr.Candidates = append(lookupErr.Candidates, Candidate{ID: all[i].ID, Name: all[i].Name, Project: project})
+		}
+		if c.Choose == nil {
+			return zero, lookupErr
+		}
+		choice, err := c.Choose(lookupErr)
+		if err != nil {
+			return zero, err
+		}
+		if choice < 0 || choice >= len(hits) {
+			return zero, lookupErr
+		}
+		return items[hits[choice]], nil
+	}
+	return zero, notFound(resource, field, query)
+}
+
+// matchNames returns the indexes of the names matching query in one mode.
+// Fuzzy matches are ordered best first.
+func matchNames(mode MatchMode, query string, names []named) []int {
+	lower := strings.ToLower(query)
+	var hits []int
+	scores := map[int]int{}
+	for i, n := range names {
+		ok := false
+		switch mode {
+		case MatchExact:
+			ok = n.Name == query
+		case MatchFold:
+			ok = strings.EqualFold(n.Name, query)
+		case MatchPrefix:
+			ok = strings.HasPrefix(strings.ToLower(n.Name), lower)
+		case MatchSubstring:
+			ok = strings.Contains(strings.ToLower(n.Name), lower)
+		case MatchFuzzy:
+			var score int
+			score, ok = fuzzyScore(strings.ToLower(n.Name), lower)
+			scores[i] = score
+		case MatchIDPrefix:
+			ok = query != "" && strings.HasPrefix(n.ID, query)
+		}
+		if ok {
+			hits = append(hits, i)
+		}
+	}
+	if mode == MatchFuzzy {
+		sort.SliceStable(hits, func(a, b int) bool { return scores[hits[a]] < scores[hits[b]] })
+	}
+	return hits
+}
+
+// fuzzyScore reports whether query's characters appear in name in order,
+// scoring the match by the length of the span it covers (lower is better).
+func fuzzyScore(name, query string) (int, bool) {
+	if query == "" {
+		return 0, false
+	}
+	start, pos := -1, 0
+	for _, r := range query {
+		i := strings.IndexRune(name[pos:], r)
+		if i < 0 {
+			return 0, false
+		}
+		if start < 0 {
+			start = pos + i
+		}
+		pos += i + utf8.RuneLen(r)
+	}
+	return pos - start, true
+}
+
+// projectNames maps project IDs to names for describing candidates. Lookup
+// failures leave candidates with the bare project ID.
+func (c *Client) projectNames(ctx context.Context, resource string) map[string]string {
+	names := map[string]string{}
+	if resource == "label" || resource == "filter" {
+		return names
+	}
+	projects, err := c.lookupProjects(ctx)
+	if err != nil {
+		return names
+	}
+	for _, project := range projects {
+		names[project.ID] = project.Name
+	}
+	return names
+}
@@ -0,0 +1,149 @@
+package todi
+
+import (
+	"context"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Default retry settings used by NewClient.
+const (
+	DefaultMaxRetries    = 3
+	DefaultRetryBaseWait = 500 * time.Millisecond
+	DefaultRetryMaxWait  = 30 * time.Second
+)
+
+// RetryPolicy controls how failed requests are retried.
+type RetryPolicy struct {
+	// MaxRetries is the number of retries after the first attempt. Zero disables retries.
+	MaxRetries int
+	// BaseWait is the backoff ceiling for the first retry; it doubles per attempt.
+	BaseWait time.Duration
+	// MaxWait caps both the backoff ceiling and any Retry-After value.
+	MaxWait time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used by NewClient.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxRetries: DefaultMaxRetries,
+		BaseWait:   DefaultRetryBaseWait,
+		MaxWait:    DefaultRetryMaxWait,
+	}
+}
+
+// backoff returns a full-jitter delay for the given retry attempt (1-based).
+func (p RetryPolicy) backoff(attempt int) time.Duration {
+	ceiling := p.BaseWait
+	for i := 1; i < attempt && ceiling < p.MaxWait; i++ {
+		ceiling *= 2
+	}
+	if p.MaxWait > 0 && ceiling > p.MaxWait {
+		ceiling = p.MaxWait
+	}
+	if ceiling <= 0 {
+		return 0
+	}
+	return time.Duration(rand.Int64N(int64(ceiling) + 1))
+}
+
+// wait returns how long to sleep before the given retry attempt, preferring Retry-After.
+func (p RetryPolicy) wait(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if delay, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			if p.MaxWait > 0 && delay > p.MaxWait {
+				return p.MaxWait
+			}
+			return delay
+		}
+	}
+	return p.backoff(attempt)
+}
+
+// shouldRetry reports whether a request may be sent again after the given outcome.
+//
+// Idempotent methods are retried on transport errors, 429 and transient 5xx
+// responses. POST requests are always retried on 429, since rate-limited
+// requests are rejected before processing, but are only retried on transport
+// errors or 5xx when they carry an X-Request-Id the server can deduplicate on.
+func shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if err == nil && resp == nil {
+		return false
+	}
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		return true
+	}
+	transient := err != nil || (resp != nil && retryableStatus(resp.StatusCode))
+	if !transient {
+		return false
+	}
+	if isIdempotent(req.Method) {
+		return true
+	}
+	return req.Header.Get("X-Request-Id") != ""
+}
+
+func retryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// parseRetryAfter parses a Retry-After header in delta-seconds or HTTP-date form.
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	when, err := http.ParseTime(value)
+	if err != nil {
+		return 0, false
+	}
+	delay := when.Sub(now)
+	if delay < 0 {
+		delay = 0
+	}
+	return delay, true
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -2,9 +2,8 @@ package todi
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/url"
-	"strconv"
 )
 
 // ListSections fetches a page of sections.
@@ -18,26 +17,19 @@ func (c *Client) ListSections(ctx context.Context, params map[string]string) ([]
 
 // ListSectionsAll fetches all sections across pages.
 func (c *Client) ListSectionsAll(ctx context.Context, params map[string]string) ([]Section, error) {
-	if params == nil {
-		params = map[string]string{}
-	}
-	params["limit"] = strconv.Itoa(200)
 	var all []Section
-	cursor := ""
-	for {
-		if cursor != "" {
-			params["cursor"] = cursor
-		}
-		page, next, err := c.ListSections(ctx, params)
-		if err != nil {
-			return nil, err
-		}
+	key := cacheKey("sections", params)
+	if c.cacheGet(key, &all) {
+		return all, nil
+	}
+	err := EachPage(ctx, params, c.ListSections, func(page []Section) error {
 		all = append(all, page...)
-		if next == "" {
-			break
-		}
-		cursor = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	c.cachePut(key, all)
 	return all, nil
 }
 
@@ -69,29 +61,36 @@ func (c *Client) DeleteSection(ctx context.Context, id string) ([]byte, error) {
 	return c.delete(ctx, "/api/v1/sections/"+url.PathEscape(id))
 }
 
-// FindSectionByName returns the section for a unique name match.
+// FindSectionByName returns the section matching name under the client's match
+// modes for sections.
 func (c *Client) FindSectionByName(ctx context.Context, name, projectID string) (Section, error) {
-	params := map[string]string{}
-	if projectID != "" {
-		params["project_id"] = projectID
-	}
-	sections, err := c.ListSectionsAll(ctx, params)
+	sections, err := c.lookupSections(ctx, projectID)
 	if err != nil {
 		return Section{}, err
 	}
-	matches := make([]Section, 0, 2)
-	for _, section := range sections {
-		if section.Name == name {
-			matches = append(matches, section)
-		}
+	return findByName(ctx, c, "section", "name", name, sections, func(item Section) named {
+		return named{ID: item.ID, Name: item.Name, ProjectID: item.ProjectID}
+	})
+}
+
+// lookupSections returns the active sections that name lookups search, in
+// one project if projectID is set: those of the sync state, or the cached
+// section listing when offline without one.
+func (c *Client) lookupSections(ctx context.Context, projectID string) ([]Section, error) {
+	state, err := c.synced(ctx)
+	if errors.Is(err, ErrOffline) {
+		return c.ListSectionsAll(ctx, map[string]string{"project_id": projectID})
 	}
-	if len(matches) == 0 {
-		return Section{}, fmt.Errorf("section not found: %s", name)
+	if err != nil {
+		return nil, err
 	}
-	if len(matches) > 1 {
-		return Section{}, fmt.Errorf("section name not unique: %s", name)
+	sections := make([]Section, 0, len(state.Sections))
+	for _, section := range state.Sections {
+		if !section.IsArchived && (projectID == "" || section.ProjectID == projectID) {
+			sections = append(sections, section)
+		}
 	}
-	return matches[0], nil
+	return sections, nil
 }
 
 // FindSectionIDByName returns the section ID for a unique name match.
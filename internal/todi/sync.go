@@ -0,0 +1,409 @@
+package todi
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"slices"
+)
+
+// Sync API resource types.
+const (
+	ResourceItems     = "items"
+	ResourceProjects  = "projects"
+	ResourceSections  = "sections"
+	ResourceLabels    = "labels"
+	ResourceNotes     = "notes"
+	ResourceReminders = "reminders"
+	ResourceFilters   = "filters"
+)
+
+// FullSyncToken requests a full sync instead of an incremental one.
+const FullSyncToken = "*"
+
+// MaxCommandsPerSync is the largest command batch the Sync API accepts.
+const MaxCommandsPerSync = 100
+
+// Command is a single Sync API write command.
+type Command struct {
+	Type   string         `json:"type"`
+	UUID   string         `json:"uuid"`
+	TempID string         `json:"temp_id,omitempty"`
+	Args   map[string]any `json:"args"`
+}
+
+// NewCommand builds a command with a fresh UUID.
+func NewCommand(commandType string, args map[string]any) Command {
+	if args == nil {
+		args = map[string]any{}
+	}
+	return Command{Type: commandType, UUID: newRequestID(), Args: args}
+}
+
+// NewTempID returns a client-side ID for objects created by a command.
+func NewTempID() string {
+	return newRequestID()
+}
+
+// CommandStatus is the per-command outcome reported in sync_status.
+type CommandStatus struct {
+	OK        bool
+	ErrorCode int
+	Error     string
+}
+
+// UnmarshalJSON decodes either "ok" or an error object.
+func (s *CommandStatus) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		s.OK = text == "ok"
+		if !s.OK {
+			s.Error = text
+		}
+		return nil
+	}
+	var payload struct {
+		ErrorCode int    `json:"error_code"`
+		Error     string `json:"error"`
+	}
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	s.ErrorCode = payload.ErrorCode
+	s.Error = payload.Error
+	return nil
+}
+
+// SyncResponse holds the resources and command results returned by a sync call.
+type SyncResponse struct {
+	SyncToken     string                   `json:"sync_token"`
+	FullSync      bool                     `json:"full_sync"`
+	Items         []Task                   `json:"items"`
+	Projects      []Project                `json:"projects"`
+	Sections      []Section                `json:"sections"`
+	Labels        []Label                  `json:"labels"`
+	Notes         []Comment                `json:"notes"`
+	Reminders     []Reminder               `json:"reminders"`
+	Filters       []Filter                 `json:"filters"`
+	SyncStatus    map[string]CommandStatus `json:"sync_status"`
+	TempIDMapping map[string]string        `json:"temp_id_mapping"`
+}
+
+// Sync performs a raw Sync API call. Pass FullSyncToken for a full read.
+func (c *Client) Sync(ctx context.Context, syncToken string, resourceTypes []string, commands []Command) (SyncResponse, error) {
+	form := url.Values{}
+	if syncToken != "" {
+		form.Set("sync_token", syncToken)
+	}
+	if len(resourceTypes) > 0 {
+		data, err := json.Marshal(resourceTypes)
+		if err != nil {
+			return SyncResponse{}, err
+		}
+		form.Set("resource_types", string(data))
+	}
+	if len(commands) > 0 {
+		data, err := json.Marshal(commands)
+		if err != nil {
+			return SyncResponse{}, err
+		}
+		form.Set("commands", string(data))
+		defer c.invalidate()
+	}
+	var resp SyncResponse
+	if _, err := c.postForm(ctx, "/api/v1/sync", form, &resp); err != nil {
+		return SyncResponse{}, err
+	}
+	return resp, nil
+}
+
+// CommandError reports a command rejected by the Sync API.
+type CommandError struct {
+	Type    string
+	UUID    string
+	Code    int
+	Message string
+}
+
+func (e *CommandError) Error() string {
+	if e.Code != 0 {
+		return fmt.Sprintf("%s failed: %s (code %d)", e.Type, e.Message, e.Code)
+	}
+	return fmt.Sprintf("%s failed: %s", e.Type, e.Message)
+}
+
+// CommandResult pairs a command with its outcome.
+type CommandResult struct {
+	Command Command
+	// ID is the real ID for commands that created an object with a temp ID.
+	ID  string
+	Err error
+}
+
+// ExecuteCommands sends commands in batches and returns a result per command.
+//
+// Temp IDs created in earlier batches are rewritten to their real IDs before
+// later batches are sent, so callers may reference them freely. On transport
+// or API failure the results gathered so far are returned with the error.
+func (c *Client) ExecuteCommands(ctx context.Context, commands []Command) ([]CommandResult, map[string]string, error) {
+	results := make([]CommandResult, 0, len(commands))
+	mapping := map[string]string{}
+	for start := 0; start < len(commands); start += MaxCommandsPerSync {
+		end := min(start+MaxCommandsPerSync, len(commands))
+		batch := make([]Command, 0, end-start)
+		for _, cmd := range commands[start:end] {
+			cmd.Args = remapTempIDs(cmd.Args, mapping)
+			batch = append(batch, cmd)
+		}
+		resp, err := c.Sync(ctx, "", nil, batch)
+		if err != nil {
+			return results, mapping, err
+		}
+		for tempID, realID := range resp.TempIDMapping {
+			mapping[tempID] = realID
+		}
+		for _, cmd := range batch {
+			results = append(results, commandResult(cmd, resp, mapping))
+		}
+	}
+	return results, mapping, nil
+}
+
+// commandResult reads the outcome of cmd from the response of the sync
+// call that sent it.
+func commandResult(cmd Command, resp SyncResponse, mapping map[string]string) CommandResult {
+	result := CommandResult{Command: cmd}
+	if cmd.TempID != "" {
+		result.ID = mapping[cmd.TempID]
+	}
+	status, ok := resp.SyncStatus[cmd.UUID]
+	switch {
+	case !ok:
+		result.Err = &CommandError{Type: cmd.Type, UUID: cmd.UUID, Message: "no status returned"}
+	case !status.OK:
+		result.Err = &CommandError{Type: cmd.Type, UUID: cmd.UUID, Code: status.ErrorCode, Message: status.Error}
+	}
+	return result
+}
+
+// ExecuteCommand sends a single command and returns the real ID of any
+// object it created.
+func (c *Client) ExecuteCommand(ctx context.Context, cmd Command) (string, error) {
+	results, _, err := c.ExecuteCommands(ctx, []Command{cmd})
+	if err != nil {
+		return "", err
+	}
+	if len(results) == 0 {
+		return "", &CommandError{Type: cmd.Type, UUID: cmd.UUID, Message: "no status returned"}
+	}
+	return results[0].ID, results[0].Err
+}
+
+// remapTempIDs rewrites temp IDs created by earlier commands to real IDs
+// anywhere in args, including nested lists and objects.
+func remapTempIDs(args map[string]any, mapping map[string]string) map[string]any {
+	if len(mapping) == 0 {
+		return args
+	}
+	return remapTempIDValue(args, mapping).(map[string]any)
+}
+
+func remapTempIDValue(value any, mapping map[string]string) any {
+	switch v := value.(type) {
+	case string:
+		if realID, ok := mapping[v]; ok {
+			return realID
+		}
+		return v
+	case []string:
+		ids := make([]string, len(v))
+		for i, id := range v {
+			ids[i] = remapTempIDValue(id, mapping).(string)
+		}
+		return ids
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = remapTempIDValue(item, mapping)
+		}
+		return items
+	case map[string]any:
+		remapped := make(map[string]any, len(v))
+		for key, item := range v {
+			remapped[key] = remapTempIDValue(item, mapping)
+		}
+		return remapped
+	default:
+		return value
+	}
+}
+
+// SyncState accumulates resources across full and incremental syncs.
+//
+// The zero value is ready to use; the first Refresh performs a full sync.
+// SyncState is JSON-serializable so callers can persist it between runs.
+type SyncState struct {
+	SyncToken     string     `json:"sync_token"`
+	ResourceTypes []string   `json:"resource_types"`
+	Items         []Task     `json:"items,omitempty"`
+	Projects      []Project  `json:"projects,omitempty"`
+	Sections      []Section  `json:"sections,omitempty"`
+	Labels        []Label    `json:"labels,omitempty"`
+	Notes         []Comment  `json:"notes,omitempty"`
+	Reminders     []Reminder `json:"reminders,omitempty"`
+	Filters       []Filter   `json:"filters,omitempty"`
+}
+
+// Refresh brings the state up to date for the given resource types.
+//
+// A full sync is used when the state is empty or the requested resource
+// types differ from the previous call, since sync tokens cover every type
+// that was requested with them.
+func (s *SyncState) Refresh(ctx context.Context, c *Client, resourceTypes []string) error {
+	token := s.SyncToken
+	if token == "" || !slices.Equal(s.ResourceTypes, resourceTypes) {
+		token = FullSyncToken
+	}
+	resp, err := c.Sync(ctx, token, resourceTypes, nil)
+	if err != nil {
+		return err
+	}
+	s.Apply(resp, resourceTypes)
+	return nil
+}
+
+// Apply merges a sync response into the state.
+func (s *SyncState) Apply(resp SyncResponse, resourceTypes []string) {
+	if resp.FullSync {
+		s.ResourceTypes = slices.Clone(resourceTypes)
+	}
+	for _, resource := range resourceTypes {
+		switch resource {
+		case ResourceItems:
+			s.Items = mergeByID(s.Items, resp.Items, resp.FullSync, func(t Task) (string, bool) { return t.ID, t.IsDeleted })
+		case ResourceProjects:
+			s.Projects = mergeByID(s.Projects, resp.Projects, resp.FullSync, func(p Project) (string, bool) { return p.ID, p.IsDeleted })
+		case ResourceSections:
+			s.Sections = mergeByID(s.Sections, resp.Sections, resp.FullSync, func(sec Section) (string, bool) { return sec.ID, sec.IsDeleted })
+		case ResourceLabels:
+			s.Labels = mergeByID(s.Labels, resp.Labels, resp.FullSync, func(l Label) (string, bool) { return l.ID, l.IsDeleted })
+		case ResourceNotes:
+			s.Notes = mergeByID(s.Notes, resp.Notes, resp.FullSync, func(n Comment) (string, bool) { return n.ID, n.IsDeleted })
+		case ResourceReminders:
+			s.Reminders = mergeByID(s.Reminders, resp.Reminders, resp.FullSync, func(r Reminder) (string, bool) { return r.ID, r.IsDeleted })
+		case ResourceFilters:
+			s.Filters = mergeByID(s.Filters, resp.Filters, resp.FullSync, func(f Filter) (string, bool) { return f.ID, f.IsDeleted })
+		}
+	}
+	if resp.SyncToken != "" {
+		s.SyncToken = resp.SyncToken
+	}
+}
+
+// mergeByID upserts updates into existing by ID and drops deleted entries.
+// existing is copied first, so slices handed out earlier stay unchanged.
+func mergeByID[T any](existing, updates []T, replace bool, key func(T) (string, bool)) []T {
+	if replace {
+		existing = nil
+	}
+	existing = slices.Clone(existing)
+	index := make(map[string]int, len(existing))
+	for i, item := range existing {
+		id, _ := key(item)
+		index[id] = i
+	}
+	deleted := map[string]bool{}
+	for _, item := range updates {
+		id, isDeleted := key(item)
+		if isDeleted {
+			deleted[id] = true
+			continue
+		}
+		if i, ok := index[id]; ok {
+			existing[i] = item
+			continue
+		}
+		index[id] = len(existing)
+		existing = append(existing, item)
+	}
+	if len(deleted) == 0 {
+		return existing
+	}
+	kept := existing[:0]
+	for _, item := range existing {
+		if id, _ := key(item); !deleted[id] {
+			kept = append(kept, item)
+		}
+	}
+	return kept
+}
+
+// syncResources are the resource types kept in the client's sync state.
+// They are always requested together so one sync token covers them all.
+var syncResources = []string{ResourceItems, ResourceProjects, ResourceSections, ResourceLabels, ResourceReminders, ResourceFilters}
+
+// SyncCache is implemented by caches that keep the sync state between runs,
+// so later runs fetch only what changed.
+type SyncCache interface {
+	GetSync(out any) bool
+	PutSync(value any)
+}
+
+// synced returns the client's sync state, brought up to date with an
+// incremental sync at most once between writes. The state is loaded from
+// the cache on first use and saved after every refresh. Offline, a saved
+// state is returned as is.
+func (c *Client) synced(ctx context.Context) (SyncState, error) {
+	c.syncMu.Lock()
+	defer c.syncMu.Unlock()
+	if c.syncState == nil {
+		c.syncState = &SyncState{}
+		if store, ok := c.Cache.(SyncCache); ok && !store.GetSync(c.syncState) {
+			c.syncState = &SyncState{}
+		}
+	}
+	if c.syncCurrent || (c.Offline && c.syncState.SyncToken != "") {
+		return *c.syncState, nil
+	}
+	if err := c.syncState.Refresh(ctx, c, syncResources); err != nil {
+		return SyncState{}, err
+	}
+	c.syncCurrent = true
+	if store, ok := c.Cache.(SyncCache); ok {
+		store.PutSync(c.syncState)
+	}
+	return *c.syncState, nil
+}
+
+// staleSync marks the sync state as needing a refresh after a write.
+func (c *Client) staleSync() {
+	c.syncMu.Lock()
+	c.syncCurrent = false
+	c.syncMu.Unlock()
+}
+
+// executeSynced sends cmd along with an incremental sync of the client's
+// state, so the state reflects the command without another request. It
+// returns the real ID of any object the command created.
+func (c *Client) executeSynced(ctx context.Context, cmd Command) (string, error) {
+	before, err := c.synced(ctx)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.Sync(ctx, before.SyncToken, syncResources, []Command{cmd})
+	if err != nil {
+		return "", err
+	}
+	c.syncMu.Lock()
+	if c.syncState.SyncToken == before.SyncToken {
+		c.syncState.Apply(resp, syncResources)
+		c.syncCurrent = true
+		if store, ok := c.Cache.(SyncCache); ok {
+			store.PutSync(c.syncState)
+		}
+	}
+	c.syncMu.Unlock()
+	result := commandResult(cmd, resp, resp.TempIDMapping)
+	return result.ID, result.Err
+}
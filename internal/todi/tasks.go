@@ -2,9 +2,8 @@ package todi
 
 import (
 	"context"
-	"fmt"
+	"errors"
 	"net/url"
-	"strconv"
 )
 
 // ListTasks fetches a page of tasks.
@@ -18,25 +17,52 @@ func (c *Client) ListTasks(ctx context.Context, params map[string]string) ([]Tas
 
 // ListTasksAll fetches all tasks across pages.
 func (c *Client) ListTasksAll(ctx context.Context, params map[string]string) ([]Task, error) {
-	if params == nil {
-		params = map[string]string{}
+	var all []Task
+	key := cacheKey("tasks", params)
+	if c.cacheGet(key, &all) {
+		return all, nil
+	}
+	err := EachPage(ctx, params, c.ListTasks, func(page []Task) error {
+		all = append(all, page...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.cachePut(key, all)
+	return all, nil
+}
+
+// EachTaskPage calls fn with each page of tasks as it arrives, so callers
+// can stream large listings. A cached listing is passed in one call;
+// streamed pages are not cached.
+func (c *Client) EachTaskPage(ctx context.Context, params map[string]string, fn func([]Task) error) error {
+	var cached []Task
+	if c.cacheGet(cacheKey("tasks", params), &cached) {
+		return fn(cached)
 	}
-	params["limit"] = strconv.Itoa(200)
+	return EachPage(ctx, params, c.ListTasks, fn)
+}
+
+// FilterTasks fetches a page of tasks matching a filter query.
+func (c *Client) FilterTasks(ctx context.Context, params map[string]string) ([]Task, string, error) {
+	var resp listResponse[Task]
+	if err := c.get(ctx, "/api/v1/tasks/filter", params, &resp); err != nil {
+		return nil, "", err
+	}
+	return resp.Results, resp.NextCursor, nil
+}
+
+// FilterTasksAll fetches all tasks matching a filter query across pages.
+// Results are not cached since queries like "today" depend on the clock.
+func (c *Client) FilterTasksAll(ctx context.Context, params map[string]string) ([]Task, error) {
 	var all []Task
-	cursor := ""
-	for {
-		if cursor != "" {
-			params["cursor"] = cursor
-		}
-		page, next, err := c.ListTasks(ctx, params)
-		if err != nil {
-			return nil, err
-		}
+	err := EachPage(ctx, params, c.FilterTasks, func(page []Task) error {
 		all = append(all, page...)
-		if next == "" {
-			break
-		}
-		cursor = next
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 	return all, nil
 }
@@ -79,6 +105,38 @@ func (c *Client) ReopenTask(ctx context.Context, id string) ([]byte, error) {
 	return c.post(ctx, "/api/v1/tasks/"+url.PathEscape(id)+"/reopen", nil, nil)
 }
 
+// MoveTarget is where MoveTasks puts tasks. Set exactly one field; the
+// Sync API moves a task into a section's project and a parent's project
+// implicitly.
+type MoveTarget struct {
+	ProjectID string
+	SectionID string
+	ParentID  string
+}
+
+func (t MoveTarget) args(id string) map[string]any {
+	args := map[string]any{"id": id}
+	switch {
+	case t.ParentID != "":
+		args["parent_id"] = t.ParentID
+	case t.SectionID != "":
+		args["section_id"] = t.SectionID
+	default:
+		args["project_id"] = t.ProjectID
+	}
+	return args
+}
+
+// MoveTasks moves tasks with item_move commands and returns a result per task.
+func (c *Client) MoveTasks(ctx context.Context, ids []string, target MoveTarget) ([]CommandResult, error) {
+	commands := make([]Command, 0, len(ids))
+	for _, id := range ids {
+		commands = append(commands, NewCommand("item_move", target.args(id)))
+	}
+	results, _, err := c.ExecuteCommands(ctx, commands)
+	return results, err
+}
+
 // QuickAdd creates a task using Todoist quick-add syntax.
 func (c *Client) QuickAdd(ctx context.Context, body map[string]any) (Task, []byte, error) {
 	var resp struct {
@@ -88,23 +146,50 @@ func (c *Client) QuickAdd(ctx context.Context, body map[string]any) (Task, []byt
 	return resp.Task, raw, err
 }
 
-// FindTaskByContent returns the task for a unique content match.
+// FindTaskByContent returns the task whose content matches title under the
+// client's match modes for tasks.
 func (c *Client) FindTaskByContent(ctx context.Context, title string) (Task, error) {
-	tasks, err := c.ListTasksAll(ctx, nil)
+	tasks, err := c.lookupTasks(ctx)
 	if err != nil {
 		return Task{}, err
 	}
-	matches := make([]Task, 0, 2)
-	for _, task := range tasks {
-		if task.Content == title {
-			matches = append(matches, task)
+	return findByName(ctx, c, "task", "title", title, tasks, taskNamed)
+}
+
+// lookupTasks returns the open tasks that name lookups search: those of the
+// sync state, or the cached task listing when offline without one.
+func (c *Client) lookupTasks(ctx context.Context) ([]Task, error) {
+	state, err := c.synced(ctx)
+	if errors.Is(err, ErrOffline) {
+		return c.ListTasksAll(ctx, nil)
+	}
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]Task, 0, len(state.Items))
+	for _, task := range state.Items {
+		if !task.IsCompleted {
+			tasks = append(tasks, task)
 		}
 	}
-	if len(matches) == 0 {
-		return Task{}, fmt.Errorf("task not found: %s", title)
+	return tasks, nil
+}
+
+func taskNamed(task Task) named {
+	return named{ID: task.ID, Name: task.Content, ProjectID: task.ProjectID}
+}
+
+// FindTasksByContent resolves several titles against a single task listing.
+// errs[i] is set for each title that does not resolve.
+func (c *Client) FindTasksByContent(ctx context.Context, titles []string) (tasks []Task, errs []error, err error) {
+	all, err := c.lookupTasks(ctx)
+	if err != nil {
+		return nil, nil, err
 	}
-	if len(matches) > 1 {
-		return Task{}, fmt.Errorf("task title not unique: %s", title)
+	tasks = make([]Task, len(titles))
+	errs = make([]error, len(titles))
+	for i, title := range titles {
+		tasks[i], errs[i] = findByName(ctx, c, "task", "title", title, all, taskNamed)
 	}
-	return matches[0], nil
+	return tasks, errs, nil
 }
@@ -10,3 +10,18 @@ func (c *Client) GetUserInfo(ctx context.Context) (User, error) {
 	}
 	return user, nil
 }
+
+// CachedUserInfo is GetUserInfo served from the cache when possible, for
+// callers that only need settings such as the timezone.
+func (c *Client) CachedUserInfo(ctx context.Context) (User, error) {
+	var user User
+	if c.cacheGet("user", &user) {
+		return user, nil
+	}
+	user, err := c.GetUserInfo(ctx)
+	if err != nil {
+		return User{}, err
+	}
+	c.cachePut("user", user)
+	return user, nil
+}